@@ -1,7 +1,10 @@
 package qry
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 // Query is an SQL query.
@@ -9,6 +12,9 @@ type Query interface {
 	Build() (string, []any)
 }
 
+// ErrInvalidQuery is returned when a query cannot be built because of the way it has been configured.
+var ErrInvalidQuery = errors.New("invalid query")
+
 // And returns a ConditionGroup made up of many Conditions separated an AND.
 func And(conditions ...Condition) Condition {
 	return &ConditionGroup{
@@ -28,10 +34,7 @@ func Or(conditions ...Condition) Condition {
 // Equal returns a SimpleCondition that will check that the given field has the given value.
 func Equal(field Field, value any) Condition {
 	if value == nil {
-		return &RawCondition{
-			SQL:  fmt.Sprintf("%s IS NULL", field),
-			Args: []any{},
-		}
+		return IsNull(field)
 	}
 	return &SimpleCondition{
 		Field:      field,
@@ -43,32 +46,256 @@ func Equal(field Field, value any) Condition {
 // NotEqual returns a SimpleCondition that will check that the given field does not have the given value.
 func NotEqual(field Field, value any) Condition {
 	if value == nil {
+		return IsNotNull(field)
+	}
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: "!=",
+		Value:      value,
+	}
+}
+
+// GreaterThan returns a SimpleCondition that will check that the given field is greater than the given value.
+func GreaterThan(field Field, value any) Condition {
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: ">",
+		Value:      value,
+	}
+}
+
+// GreaterThanOrEqual returns a SimpleCondition that will check that the given field is greater than or equal to
+// the given value.
+func GreaterThanOrEqual(field Field, value any) Condition {
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: ">=",
+		Value:      value,
+	}
+}
+
+// LessThan returns a SimpleCondition that will check that the given field is less than the given value.
+func LessThan(field Field, value any) Condition {
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: "<",
+		Value:      value,
+	}
+}
+
+// LessThanOrEqual returns a SimpleCondition that will check that the given field is less than or equal to
+// the given value.
+func LessThanOrEqual(field Field, value any) Condition {
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: "<=",
+		Value:      value,
+	}
+}
+
+// IsNull returns a Condition that will check that the given field is NULL.
+func IsNull(field Field) Condition {
+	return &fieldCondition{
+		Field: field,
+		SQL:   "%s IS NULL",
+		Args:  []any{},
+	}
+}
+
+// IsNotNull returns a Condition that will check that the given field is not NULL.
+func IsNotNull(field Field) Condition {
+	return &fieldCondition{
+		Field: field,
+		SQL:   "%s IS NOT NULL",
+		Args:  []any{},
+	}
+}
+
+// In returns a Condition that will check that the given field has one of the given values.
+// values is expected to be a slice or array, each element of which is bound as a separate arg,
+// or a SelectQuery whose results are used as a subquery.
+// An empty slice results in a Condition that never matches.
+func In(field Field, values any) Condition {
+	if _, ok := values.(rowQuery); ok {
+		return &SimpleCondition{
+			Field:      field,
+			Comparison: "IN",
+			Value:      values,
+		}
+	}
+	args := expandValues(values)
+	if len(args) == 0 {
 		return &RawCondition{
-			SQL:  fmt.Sprintf("%s IS NOT NULL", field),
+			SQL:  "1 = 0",
 			Args: []any{},
 		}
 	}
+	return &fieldCondition{
+		Field: field,
+		SQL:   fmt.Sprintf("%%s IN (%s)", placeholders(len(args))),
+		Args:  args,
+	}
+}
+
+// NotIn returns a Condition that will check that the given field does not have any of the given values.
+// values is expected to be a slice or array, each element of which is bound as a separate arg,
+// or a SelectQuery whose results are used as a subquery.
+// An empty slice results in a Condition that always matches.
+func NotIn(field Field, values any) Condition {
+	if _, ok := values.(rowQuery); ok {
+		return &SimpleCondition{
+			Field:      field,
+			Comparison: "NOT IN",
+			Value:      values,
+		}
+	}
+	args := expandValues(values)
+	if len(args) == 0 {
+		return &RawCondition{
+			SQL:  "1 = 1",
+			Args: []any{},
+		}
+	}
+	return &fieldCondition{
+		Field: field,
+		SQL:   fmt.Sprintf("%%s NOT IN (%s)", placeholders(len(args))),
+		Args:  args,
+	}
+}
+
+// Exists returns a Condition that will check that the given query returns at least one row.
+func Exists(query Query) Condition {
+	return &ExistsCondition{
+		Query: query,
+	}
+}
+
+// NotExists returns a Condition that will check that the given query does not return any rows.
+func NotExists(query Query) Condition {
+	return &ExistsCondition{
+		Query: query,
+		Not:   true,
+	}
+}
+
+// Between returns a Condition that will check that the given field is between the given values, inclusive.
+func Between(field Field, from any, to any) Condition {
+	return &fieldCondition{
+		Field: field,
+		SQL:   "%s BETWEEN ? AND ?",
+		Args:  []any{from, to},
+	}
+}
+
+// Like returns a Condition that will check that the given field matches the given pattern.
+// The pattern is used as is, so any wildcards it contains will be respected.
+func Like(field Field, pattern any) Condition {
 	return &SimpleCondition{
 		Field:      field,
-		Comparison: "!=",
-		Value:      value,
+		Comparison: "LIKE",
+		Value:      pattern,
+	}
+}
+
+// NotLike returns a Condition that will check that the given field does not match the given pattern.
+// The pattern is used as is, so any wildcards it contains will be respected.
+func NotLike(field Field, pattern any) Condition {
+	return &SimpleCondition{
+		Field:      field,
+		Comparison: "NOT LIKE",
+		Value:      pattern,
+	}
+}
+
+// LikeEscapeChar is the escape character used by EscapeLike, Contains, StartsWith and EndsWith.
+// It is not a backslash because backslashes are treated differently in string literals across databases.
+const LikeEscapeChar = "!"
+
+// EscapeLike escapes any LIKE wildcards in the given value so that it is matched literally.
+// The result must be used alongside an ESCAPE clause using LikeEscapeChar.
+func EscapeLike(value string) string {
+	return strings.NewReplacer(
+		LikeEscapeChar, LikeEscapeChar+LikeEscapeChar,
+		"%", LikeEscapeChar+"%",
+		"_", LikeEscapeChar+"_",
+	).Replace(value)
+}
+
+// Contains returns a Condition that will check that the given field contains the given value.
+// Any wildcards in the value are escaped.
+func Contains(field Field, value string) Condition {
+	return escapedLike(field, "%"+EscapeLike(value)+"%")
+}
+
+// StartsWith returns a Condition that will check that the given field starts with the given value.
+// Any wildcards in the value are escaped.
+func StartsWith(field Field, value string) Condition {
+	return escapedLike(field, EscapeLike(value)+"%")
+}
+
+// EndsWith returns a Condition that will check that the given field ends with the given value.
+// Any wildcards in the value are escaped.
+func EndsWith(field Field, value string) Condition {
+	return escapedLike(field, "%"+EscapeLike(value))
+}
+
+func escapedLike(field Field, pattern string) Condition {
+	return &fieldCondition{
+		Field: field,
+		SQL:   fmt.Sprintf("%%s LIKE ? ESCAPE '%s'", LikeEscapeChar),
+		Args:  []any{pattern},
+	}
+}
+
+// Not returns a Condition that negates the given Condition.
+func Not(condition Condition) Condition {
+	return &NotCondition{
+		Condition: condition,
 	}
 }
 
 // JsonArrayContains returns a Condition that will check if the given value exists in a JSON array stored under
 // the given field.
 func JsonArrayContains(field Field, value any) Condition {
-	return &RawCondition{
-		SQL:  fmt.Sprintf("JSON_CONTAINS(%s, ?, '$') = 1", field),
-		Args: []any{value},
+	return &fieldCondition{
+		Field: field,
+		SQL:   "JSON_CONTAINS(%s, ?, '$') = 1",
+		Args:  []any{value},
 	}
 }
 
 // NotJsonArrayContains returns a Condition that will check that the given value does not exist in a JSON array stored under
 // the given field.
 func NotJsonArrayContains(field Field, value any) Condition {
-	return &RawCondition{
-		SQL:  fmt.Sprintf("JSON_CONTAINS(%s, ?, '$') = 0", field),
-		Args: []any{value},
+	return &fieldCondition{
+		Field: field,
+		SQL:   "JSON_CONTAINS(%s, ?, '$') = 0",
+		Args:  []any{value},
+	}
+}
+
+// expandValues returns each element of the given slice or array as a separate value.
+// Values that are not slices or arrays are returned as a single value.
+// Byte slices are treated as a single value since they are commonly bound as-is.
+func expandValues(values any) []any {
+	if values == nil {
+		return []any{}
 	}
+	if _, ok := values.([]byte); ok {
+		return []any{values}
+	}
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []any{values}
+	}
+	res := make([]any, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		res[i] = v.Index(i).Interface()
+	}
+	return res
+}
+
+// placeholders returns n comma separated placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
 }
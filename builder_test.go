@@ -385,3 +385,238 @@ func TestOr(t *testing.T) {
 		})
 	}
 }
+
+func TestComparisons(t *testing.T) {
+	type def struct {
+		name      string
+		condition qry.Condition
+		expStmt   string
+		expArgs   []any
+	}
+	tests := []def{
+		{
+			name:      "GreaterThan",
+			condition: qry.GreaterThan("age", 18),
+			expStmt:   "age > ?",
+			expArgs:   []any{18},
+		},
+		{
+			name:      "GreaterThanOrEqual",
+			condition: qry.GreaterThanOrEqual("age", 18),
+			expStmt:   "age >= ?",
+			expArgs:   []any{18},
+		},
+		{
+			name:      "LessThan",
+			condition: qry.LessThan("age", 65),
+			expStmt:   "age < ?",
+			expArgs:   []any{65},
+		},
+		{
+			name:      "LessThanOrEqual",
+			condition: qry.LessThanOrEqual("age", 65),
+			expStmt:   "age <= ?",
+			expArgs:   []any{65},
+		},
+		{
+			name:      "IsNull",
+			condition: qry.IsNull("deleted_at"),
+			expStmt:   "deleted_at IS NULL",
+			expArgs:   []any{},
+		},
+		{
+			name:      "IsNotNull",
+			condition: qry.IsNotNull("deleted_at"),
+			expStmt:   "deleted_at IS NOT NULL",
+			expArgs:   []any{},
+		},
+		{
+			name:      "Between",
+			condition: qry.Between("age", 18, 65),
+			expStmt:   "age BETWEEN ? AND ?",
+			expArgs:   []any{18, 65},
+		},
+		{
+			name:      "Like",
+			condition: qry.Like("name", "T%m"),
+			expStmt:   "name LIKE ?",
+			expArgs:   []any{"T%m"},
+		},
+		{
+			name:      "NotLike",
+			condition: qry.NotLike("name", "T%m"),
+			expStmt:   "name NOT LIKE ?",
+			expArgs:   []any{"T%m"},
+		},
+		{
+			name:      "Contains",
+			condition: qry.Contains("name", "100%_!"),
+			expStmt:   "name LIKE ? ESCAPE '!'",
+			expArgs:   []any{"%100!%!_!!%"},
+		},
+		{
+			name:      "StartsWith",
+			condition: qry.StartsWith("name", "a_b"),
+			expStmt:   "name LIKE ? ESCAPE '!'",
+			expArgs:   []any{"a!_b%"},
+		},
+		{
+			name:      "EndsWith",
+			condition: qry.EndsWith("name", "a_b"),
+			expStmt:   "name LIKE ? ESCAPE '!'",
+			expArgs:   []any{"%a!_b"},
+		},
+		{
+			name:      "Not",
+			condition: qry.Not(qry.Equal("name", "Tom")),
+			expStmt:   "NOT (name = ?)",
+			expArgs:   []any{"Tom"},
+		},
+		{
+			name:      "Not empty group",
+			condition: qry.Not(qry.And()),
+			expStmt:   "",
+			expArgs:   []any{},
+		},
+		{
+			name: "Composed with And and Or",
+			condition: qry.And(
+				qry.GreaterThanOrEqual("age", 18),
+				qry.Or(qry.IsNull("deleted_at"), qry.In("status", []string{"active", "pending"})),
+			),
+			expStmt: "(age >= ? AND (deleted_at IS NULL OR status IN (?, ?)))",
+			expArgs: []any{18, "active", "pending"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := tc.condition.Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func TestIn(t *testing.T) {
+	type def struct {
+		name    string
+		field   qry.Field
+		values  any
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name:    "Int slice",
+			field:   "id",
+			values:  []int64{1, 2, 3},
+			expStmt: "id IN (?, ?, ?)",
+			expArgs: []any{int64(1), int64(2), int64(3)},
+		},
+		{
+			name:    "String array",
+			field:   "name",
+			values:  [2]string{"Tom", "Jim"},
+			expStmt: "name IN (?, ?)",
+			expArgs: []any{"Tom", "Jim"},
+		},
+		{
+			name:    "Single value",
+			field:   "id",
+			values:  1,
+			expStmt: "id IN (?)",
+			expArgs: []any{1},
+		},
+		{
+			name:    "Bytes are a single value",
+			field:   "hash",
+			values:  []byte("abc"),
+			expStmt: "hash IN (?)",
+			expArgs: []any{[]byte("abc")},
+		},
+		{
+			name:    "Empty slice",
+			field:   "id",
+			values:  []int64{},
+			expStmt: "1 = 0",
+			expArgs: []any{},
+		},
+		{
+			name:    "Nil",
+			field:   "id",
+			values:  nil,
+			expStmt: "1 = 0",
+			expArgs: []any{},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := qry.In(tc.field, tc.values).Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func TestNotIn(t *testing.T) {
+	type def struct {
+		name    string
+		field   qry.Field
+		values  any
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name:    "Int slice",
+			field:   "id",
+			values:  []int{1, 2},
+			expStmt: "id NOT IN (?, ?)",
+			expArgs: []any{1, 2},
+		},
+		{
+			name:    "Empty slice",
+			field:   "id",
+			values:  []int{},
+			expStmt: "1 = 1",
+			expArgs: []any{},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := qry.NotIn(tc.field, tc.values).Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
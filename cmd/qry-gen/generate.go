@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/TomWright/qry/internal/dbtag"
+)
+
+const qryImportPath = "github.com/TomWright/qry"
+
+// generate returns the formatted source of a file in the given package containing the generated code
+// for each of the given structs.
+func generate(pkgName string, structs []structInfo) ([]byte, error) {
+	imports := map[string]string{
+		"qry": qryImportPath,
+	}
+	for _, info := range structs {
+		primaryKey := info.primaryKey()
+		if len(primaryKey) == 0 {
+			continue
+		}
+		imports["context"] = "context"
+		imports["sql"] = "database/sql"
+		for _, field := range primaryKey {
+			for name, path := range field.Imports {
+				if existing, ok := imports[name]; ok && existing != path {
+					return nil, fmt.Errorf("%s: import %q conflicts with %q", info.Name, path, existing)
+				}
+				imports[name] = path
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by qry-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", pkgName)
+
+	buf.WriteString("import (\n")
+	standardLibrary := true
+	for _, name := range sortedImportNames(imports) {
+		path := imports[name]
+		if standardLibrary && strings.Contains(path, ".") {
+			// Separate standard library imports from the rest as goimports would.
+			standardLibrary = false
+			buf.WriteString("\n")
+		}
+		if path[strings.LastIndex(path, "/")+1:] == name {
+			fmt.Fprintf(buf, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(buf, "\t%s %q\n", name, path)
+		}
+	}
+	buf.WriteString(")\n")
+
+	for _, info := range structs {
+		generateStruct(buf, info)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("could not format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// sortedImportNames returns the names of the given imports, with standard library imports first
+// and then ordered by import path.
+func sortedImportNames(imports map[string]string) []string {
+	names := make([]string, 0, len(imports))
+	for name := range imports {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		pathI, pathJ := imports[names[i]], imports[names[j]]
+		stdI, stdJ := !strings.Contains(pathI, "."), !strings.Contains(pathJ, ".")
+		if stdI != stdJ {
+			return stdI
+		}
+		return pathI < pathJ
+	})
+	return names
+}
+
+func generateStruct(buf *bytes.Buffer, info structInfo) {
+	name := info.Name
+
+	fmt.Fprintf(buf, "\n// Columns of %s.\nconst (\n", name)
+	for _, field := range info.Fields {
+		fmt.Fprintf(buf, "\t%s qry.Field = %q\n", info.fieldConst(field), field.Tag.Name)
+	}
+	buf.WriteString(")\n")
+
+	fmt.Fprintf(buf, "\n// %sFields contains every column of %s, in the order used by %sFieldReferences.\n", name, name, name)
+	fmt.Fprintf(buf, "var %sFields = []qry.Field{\n", name)
+	for _, field := range info.Fields {
+		fmt.Fprintf(buf, "\t%s,\n", info.fieldConst(field))
+	}
+	buf.WriteString("}\n")
+
+	fmt.Fprintf(buf, "\n// %sFieldReferences returns a reference to each field of target, in the order of %sFields.\n", name, name)
+	fmt.Fprintf(buf, "func %sFieldReferences(target *%s) []any {\n\treturn []any{\n", name, name)
+	for _, field := range info.Fields {
+		fmt.Fprintf(buf, "\t\t&target.%s,\n", field.Path)
+	}
+	buf.WriteString("\t}\n}\n")
+
+	generateValues(buf, info, "Insert", "inserted", dbtag.Tag.Insertable)
+	generateValues(buf, info, "Update", "updated", dbtag.Tag.Updatable)
+
+	primaryKey := info.primaryKey()
+
+	if len(primaryKey) > 0 {
+		fmt.Fprintf(buf, "\n// %sPrimaryKeyCondition returns a Condition matching the primary key of target.\n", name)
+		fmt.Fprintf(buf, "func %sPrimaryKeyCondition(target *%s) qry.Condition {\n", name, name)
+		fmt.Fprintf(buf, "\treturn %s\n}\n", info.primaryKeyCondition(func(field fieldInfo) string {
+			return "target." + field.Path
+		}))
+
+		fmt.Fprintf(buf, "\n// %sPrimaryKeyValues returns the value of each primary key field of target.\n", name)
+		fmt.Fprintf(buf, "func %sPrimaryKeyValues(target *%s) map[qry.Field]any {\n\treturn map[qry.Field]any{\n", name, name)
+		for _, field := range primaryKey {
+			fmt.Fprintf(buf, "\t\t%s: target.%s,\n", info.fieldConst(field), field.Path)
+		}
+		buf.WriteString("\t}\n}\n")
+	}
+
+	fmt.Fprintf(buf, "\n// New%sRepository returns a TypedRepository for %s using the generated mapping.\n", name, name)
+	buf.WriteString("// Any standard select fields already set on repo are left as is.\n")
+	fmt.Fprintf(buf, "func New%sRepository(repo qry.Repository) qry.TypedRepository[%s] {\n", name, name)
+	fmt.Fprintf(buf, "\tif repo.StandardSelectFields == nil {\n\t\trepo.StandardSelectFields = %sFields\n\t}\n", name)
+	fmt.Fprintf(buf, "\treturn qry.TypedRepository[%s]{\n", name)
+	buf.WriteString("\t\tRepository: repo,\n")
+	fmt.Fprintf(buf, "\t\tStandardSelectFieldReferences: %sFieldReferences,\n", name)
+	fmt.Fprintf(buf, "\t\tStandardInsertValues: %sInsertValues,\n", name)
+	fmt.Fprintf(buf, "\t\tStandardUpdateValues: %sUpdateValues,\n", name)
+	if len(primaryKey) > 0 {
+		fmt.Fprintf(buf, "\t\tStandardUpdateCondition: %sPrimaryKeyCondition,\n", name)
+		fmt.Fprintf(buf, "\t\tStandardDeleteCondition: %sPrimaryKeyCondition,\n", name)
+		fmt.Fprintf(buf, "\t\tStandardPrimaryKeyValues: %sPrimaryKeyValues,\n", name)
+	}
+	buf.WriteString("\t}\n}\n")
+
+	if len(primaryKey) == 0 {
+		return
+	}
+
+	params := make([]string, 0, len(primaryKey))
+	for _, field := range primaryKey {
+		params = append(params, paramName(field.Name)+" "+field.Type)
+	}
+	condition := info.primaryKeyCondition(func(field fieldInfo) string {
+		return paramName(field.Name)
+	})
+	suffix := primaryKeySuffix(primaryKey)
+
+	fmt.Fprintf(buf, "\n// Find%sBy%s returns the %s with the given primary key.\n", name, suffix, name)
+	fmt.Fprintf(buf, "func Find%sBy%s(ctx context.Context, repo qry.TypedRepository[%s], %s) (*%s, error) {\n", name, suffix, name, strings.Join(params, ", "), name)
+	fmt.Fprintf(buf, "\treturn repo.QueryRowFn(ctx, func(query *qry.TypedSelectQuery[%s]) {\n", name)
+	fmt.Fprintf(buf, "\t\tquery.Condition = %s\n\t})\n}\n", condition)
+
+	fmt.Fprintf(buf, "\n// Delete%sBy%s deletes the %s with the given primary key.\n", name, suffix, name)
+	fmt.Fprintf(buf, "func Delete%sBy%s(ctx context.Context, repo qry.TypedRepository[%s], %s) (sql.Result, error) {\n", name, suffix, name, strings.Join(params, ", "))
+	fmt.Fprintf(buf, "\treturn repo.DeleteFn(ctx, func(query *qry.TypedDeleteQuery[%s]) {\n", name)
+	fmt.Fprintf(buf, "\t\tquery.Condition = func(target *%s) qry.Condition {\n", name)
+	fmt.Fprintf(buf, "\t\t\treturn %s\n\t\t}\n\t})\n}\n", condition)
+}
+
+// generateValues writes a func returning the values of each field that the include func accepts.
+// Fields whose inclusion depends on them being zero are checked at runtime.
+func generateValues(buf *bytes.Buffer, info structInfo, kind string, verb string, include func(tag dbtag.Tag, zero bool) bool) {
+	name := info.Name
+
+	fmt.Fprintf(buf, "\n// %s%sValues returns the values %s for target.\n", name, kind, verb)
+	fmt.Fprintf(buf, "func %s%sValues(target *%s) map[qry.Field]any {\n", name, kind, name)
+	buf.WriteString("\tvalues := map[qry.Field]any{\n")
+
+	var checked []fieldInfo
+	for _, field := range info.Fields {
+		whenZero, whenNotZero := include(field.Tag, true), include(field.Tag, false)
+		switch {
+		case whenZero && whenNotZero:
+			fmt.Fprintf(buf, "\t\t%s: target.%s,\n", info.fieldConst(field), field.Path)
+		case whenNotZero:
+			checked = append(checked, field)
+		}
+	}
+	buf.WriteString("\t}\n")
+
+	for _, field := range checked {
+		if field.Nilable {
+			fmt.Fprintf(buf, "\tif target.%s != nil {\n", field.Path)
+		} else {
+			fmt.Fprintf(buf, "\tif !qry.IsZero(target.%s) {\n", field.Path)
+		}
+		fmt.Fprintf(buf, "\t\tvalues[%s] = target.%s\n\t}\n", info.fieldConst(field), field.Path)
+	}
+
+	buf.WriteString("\treturn values\n}\n")
+}
+
+// fieldConst returns the name of the Field constant generated for the given field.
+func (info structInfo) fieldConst(field fieldInfo) string {
+	return info.Name + "Field" + field.Name
+}
+
+// primaryKey returns the fields that make up the primary key.
+func (info structInfo) primaryKey() []fieldInfo {
+	var fields []fieldInfo
+	for _, field := range info.Fields {
+		if field.Tag.PrimaryKey {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// primaryKeyCondition returns the source of a Condition matching the primary key against the
+// values returned by valueFn.
+func (info structInfo) primaryKeyCondition(valueFn func(field fieldInfo) string) string {
+	primaryKey := info.primaryKey()
+	conditions := make([]string, 0, len(primaryKey))
+	for _, field := range primaryKey {
+		conditions = append(conditions, fmt.Sprintf("qry.Equal(%s, %s)", info.fieldConst(field), valueFn(field)))
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "qry.And(" + strings.Join(conditions, ", ") + ")"
+}
+
+// primaryKeySuffix returns the suffix used to name the find and delete helpers.
+// A single primary key is always referred to as ID.
+func primaryKeySuffix(primaryKey []fieldInfo) string {
+	if len(primaryKey) == 1 {
+		return "ID"
+	}
+	names := make([]string, 0, len(primaryKey))
+	for _, field := range primaryKey {
+		names = append(names, field.Name)
+	}
+	return strings.Join(names, "And")
+}
+
+// paramName returns the parameter name used for the given field name, e.g. UserID becomes userID.
+func paramName(fieldName string) string {
+	runes := []rune(fieldName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		// Leave the last upper case rune of an initialism as the start of the next word.
+		upper--
+	}
+	for i := 0; i < upper; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	name := string(runes)
+	switch {
+	case token.IsKeyword(name), name == "ctx", name == "repo", name == "query":
+		return name + "Value"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,33 @@
+// Package example contains models used to test the code generated by qry-gen.
+package example
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//go:generate go run ../.. -type User,Membership -output models_qry.go
+
+// Timestamps is embedded in models that track when they were created and updated.
+type Timestamps struct {
+	CreatedAt time.Time  `db:"created_at,readonly"`
+	UpdatedAt *time.Time `db:"updated_at,omitempty"`
+}
+
+// User is a model with an auto incrementing primary key.
+type User struct {
+	ID       int64           `db:"id,pk,autoincrement"`
+	Name     string          `db:"name"`
+	Email    string          `db:"email,omitempty"`
+	Settings []byte          `db:"settings,omitempty"`
+	Metadata json.RawMessage `db:"metadata,omitempty"`
+	Password string          `db:"-"`
+	Timestamps
+}
+
+// Membership is a model with a composite primary key.
+type Membership struct {
+	UserID  int64  `db:"user_id,pk"`
+	GroupID int64  `db:"group_id,pk"`
+	Role    string `db:"role"`
+}
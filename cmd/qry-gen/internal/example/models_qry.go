@@ -0,0 +1,220 @@
+// Code generated by qry-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/TomWright/qry"
+)
+
+// Columns of User.
+const (
+	UserFieldID        qry.Field = "id"
+	UserFieldName      qry.Field = "name"
+	UserFieldEmail     qry.Field = "email"
+	UserFieldSettings  qry.Field = "settings"
+	UserFieldMetadata  qry.Field = "metadata"
+	UserFieldCreatedAt qry.Field = "created_at"
+	UserFieldUpdatedAt qry.Field = "updated_at"
+)
+
+// UserFields contains every column of User, in the order used by UserFieldReferences.
+var UserFields = []qry.Field{
+	UserFieldID,
+	UserFieldName,
+	UserFieldEmail,
+	UserFieldSettings,
+	UserFieldMetadata,
+	UserFieldCreatedAt,
+	UserFieldUpdatedAt,
+}
+
+// UserFieldReferences returns a reference to each field of target, in the order of UserFields.
+func UserFieldReferences(target *User) []any {
+	return []any{
+		&target.ID,
+		&target.Name,
+		&target.Email,
+		&target.Settings,
+		&target.Metadata,
+		&target.Timestamps.CreatedAt,
+		&target.Timestamps.UpdatedAt,
+	}
+}
+
+// UserInsertValues returns the values inserted for target.
+func UserInsertValues(target *User) map[qry.Field]any {
+	values := map[qry.Field]any{
+		UserFieldName: target.Name,
+	}
+	if !qry.IsZero(target.ID) {
+		values[UserFieldID] = target.ID
+	}
+	if !qry.IsZero(target.Email) {
+		values[UserFieldEmail] = target.Email
+	}
+	if target.Settings != nil {
+		values[UserFieldSettings] = target.Settings
+	}
+	if target.Metadata != nil {
+		values[UserFieldMetadata] = target.Metadata
+	}
+	if target.Timestamps.UpdatedAt != nil {
+		values[UserFieldUpdatedAt] = target.Timestamps.UpdatedAt
+	}
+	return values
+}
+
+// UserUpdateValues returns the values updated for target.
+func UserUpdateValues(target *User) map[qry.Field]any {
+	values := map[qry.Field]any{
+		UserFieldName: target.Name,
+	}
+	if !qry.IsZero(target.Email) {
+		values[UserFieldEmail] = target.Email
+	}
+	if target.Settings != nil {
+		values[UserFieldSettings] = target.Settings
+	}
+	if target.Metadata != nil {
+		values[UserFieldMetadata] = target.Metadata
+	}
+	if target.Timestamps.UpdatedAt != nil {
+		values[UserFieldUpdatedAt] = target.Timestamps.UpdatedAt
+	}
+	return values
+}
+
+// UserPrimaryKeyCondition returns a Condition matching the primary key of target.
+func UserPrimaryKeyCondition(target *User) qry.Condition {
+	return qry.Equal(UserFieldID, target.ID)
+}
+
+// UserPrimaryKeyValues returns the value of each primary key field of target.
+func UserPrimaryKeyValues(target *User) map[qry.Field]any {
+	return map[qry.Field]any{
+		UserFieldID: target.ID,
+	}
+}
+
+// NewUserRepository returns a TypedRepository for User using the generated mapping.
+// Any standard select fields already set on repo are left as is.
+func NewUserRepository(repo qry.Repository) qry.TypedRepository[User] {
+	if repo.StandardSelectFields == nil {
+		repo.StandardSelectFields = UserFields
+	}
+	return qry.TypedRepository[User]{
+		Repository:                    repo,
+		StandardSelectFieldReferences: UserFieldReferences,
+		StandardInsertValues:          UserInsertValues,
+		StandardUpdateValues:          UserUpdateValues,
+		StandardUpdateCondition:       UserPrimaryKeyCondition,
+		StandardDeleteCondition:       UserPrimaryKeyCondition,
+		StandardPrimaryKeyValues:      UserPrimaryKeyValues,
+	}
+}
+
+// FindUserByID returns the User with the given primary key.
+func FindUserByID(ctx context.Context, repo qry.TypedRepository[User], id int64) (*User, error) {
+	return repo.QueryRowFn(ctx, func(query *qry.TypedSelectQuery[User]) {
+		query.Condition = qry.Equal(UserFieldID, id)
+	})
+}
+
+// DeleteUserByID deletes the User with the given primary key.
+func DeleteUserByID(ctx context.Context, repo qry.TypedRepository[User], id int64) (sql.Result, error) {
+	return repo.DeleteFn(ctx, func(query *qry.TypedDeleteQuery[User]) {
+		query.Condition = func(target *User) qry.Condition {
+			return qry.Equal(UserFieldID, id)
+		}
+	})
+}
+
+// Columns of Membership.
+const (
+	MembershipFieldUserID  qry.Field = "user_id"
+	MembershipFieldGroupID qry.Field = "group_id"
+	MembershipFieldRole    qry.Field = "role"
+)
+
+// MembershipFields contains every column of Membership, in the order used by MembershipFieldReferences.
+var MembershipFields = []qry.Field{
+	MembershipFieldUserID,
+	MembershipFieldGroupID,
+	MembershipFieldRole,
+}
+
+// MembershipFieldReferences returns a reference to each field of target, in the order of MembershipFields.
+func MembershipFieldReferences(target *Membership) []any {
+	return []any{
+		&target.UserID,
+		&target.GroupID,
+		&target.Role,
+	}
+}
+
+// MembershipInsertValues returns the values inserted for target.
+func MembershipInsertValues(target *Membership) map[qry.Field]any {
+	values := map[qry.Field]any{
+		MembershipFieldUserID:  target.UserID,
+		MembershipFieldGroupID: target.GroupID,
+		MembershipFieldRole:    target.Role,
+	}
+	return values
+}
+
+// MembershipUpdateValues returns the values updated for target.
+func MembershipUpdateValues(target *Membership) map[qry.Field]any {
+	values := map[qry.Field]any{
+		MembershipFieldRole: target.Role,
+	}
+	return values
+}
+
+// MembershipPrimaryKeyCondition returns a Condition matching the primary key of target.
+func MembershipPrimaryKeyCondition(target *Membership) qry.Condition {
+	return qry.And(qry.Equal(MembershipFieldUserID, target.UserID), qry.Equal(MembershipFieldGroupID, target.GroupID))
+}
+
+// MembershipPrimaryKeyValues returns the value of each primary key field of target.
+func MembershipPrimaryKeyValues(target *Membership) map[qry.Field]any {
+	return map[qry.Field]any{
+		MembershipFieldUserID:  target.UserID,
+		MembershipFieldGroupID: target.GroupID,
+	}
+}
+
+// NewMembershipRepository returns a TypedRepository for Membership using the generated mapping.
+// Any standard select fields already set on repo are left as is.
+func NewMembershipRepository(repo qry.Repository) qry.TypedRepository[Membership] {
+	if repo.StandardSelectFields == nil {
+		repo.StandardSelectFields = MembershipFields
+	}
+	return qry.TypedRepository[Membership]{
+		Repository:                    repo,
+		StandardSelectFieldReferences: MembershipFieldReferences,
+		StandardInsertValues:          MembershipInsertValues,
+		StandardUpdateValues:          MembershipUpdateValues,
+		StandardUpdateCondition:       MembershipPrimaryKeyCondition,
+		StandardDeleteCondition:       MembershipPrimaryKeyCondition,
+		StandardPrimaryKeyValues:      MembershipPrimaryKeyValues,
+	}
+}
+
+// FindMembershipByUserIDAndGroupID returns the Membership with the given primary key.
+func FindMembershipByUserIDAndGroupID(ctx context.Context, repo qry.TypedRepository[Membership], userID int64, groupID int64) (*Membership, error) {
+	return repo.QueryRowFn(ctx, func(query *qry.TypedSelectQuery[Membership]) {
+		query.Condition = qry.And(qry.Equal(MembershipFieldUserID, userID), qry.Equal(MembershipFieldGroupID, groupID))
+	})
+}
+
+// DeleteMembershipByUserIDAndGroupID deletes the Membership with the given primary key.
+func DeleteMembershipByUserIDAndGroupID(ctx context.Context, repo qry.TypedRepository[Membership], userID int64, groupID int64) (sql.Result, error) {
+	return repo.DeleteFn(ctx, func(query *qry.TypedDeleteQuery[Membership]) {
+		query.Condition = func(target *Membership) qry.Condition {
+			return qry.And(qry.Equal(MembershipFieldUserID, userID), qry.Equal(MembershipFieldGroupID, groupID))
+		}
+	})
+}
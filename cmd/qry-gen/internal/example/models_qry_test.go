@@ -0,0 +1,95 @@
+package example_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"github.com/TomWright/qry/cmd/qry-gen/internal/example"
+	"github.com/go-test/deep"
+	"testing"
+	"time"
+)
+
+// TestGeneratedMatchesTags ensures the generated code maps structs in the same way as
+// qry.NewTypedRepositoryFromTags.
+func TestGeneratedMatchesTags(t *testing.T) {
+	updatedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	users := []*example.User{
+		{},
+		{ID: 1, Name: "Tom"},
+		{Name: "Jim", Email: "jim@example.com", Settings: []byte("{}"), Metadata: json.RawMessage("{}"), Timestamps: example.Timestamps{UpdatedAt: &updatedAt}},
+	}
+
+	generated := example.NewUserRepository(qry.Repository{Table: "users"})
+	reflected, err := qry.NewTypedRepositoryFromTags[example.User](qry.Repository{Table: "users"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff := deep.Equal(reflected.StandardSelectFields, generated.StandardSelectFields); diff != nil {
+		t.Errorf("select fields: %v", diff)
+	}
+	for _, user := range users {
+		if diff := deep.Equal(reflected.StandardSelectFieldReferences(user), generated.StandardSelectFieldReferences(user)); diff != nil {
+			t.Errorf("select field references: %v", diff)
+		}
+		if diff := deep.Equal(reflected.StandardInsertValues(user), generated.StandardInsertValues(user)); diff != nil {
+			t.Errorf("insert values: %v", diff)
+		}
+		if diff := deep.Equal(reflected.StandardUpdateValues(user), generated.StandardUpdateValues(user)); diff != nil {
+			t.Errorf("update values: %v", diff)
+		}
+		if diff := deep.Equal(reflected.StandardUpdateCondition(user), generated.StandardUpdateCondition(user)); diff != nil {
+			t.Errorf("update condition: %v", diff)
+		}
+		if diff := deep.Equal(reflected.StandardPrimaryKeyValues(user), generated.StandardPrimaryKeyValues(user)); diff != nil {
+			t.Errorf("primary key values: %v", diff)
+		}
+	}
+
+	membership := &example.Membership{UserID: 1, GroupID: 2, Role: "admin"}
+	generatedMemberships := example.NewMembershipRepository(qry.Repository{})
+	reflectedMemberships, err := qry.NewTypedRepositoryFromTags[example.Membership](qry.Repository{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff := deep.Equal(reflectedMemberships.StandardDeleteCondition(membership), generatedMemberships.StandardDeleteCondition(membership)); diff != nil {
+		t.Errorf("delete condition: %v", diff)
+	}
+	if diff := deep.Equal(reflectedMemberships.StandardPrimaryKeyValues(membership), generatedMemberships.StandardPrimaryKeyValues(membership)); diff != nil {
+		t.Errorf("primary key values: %v", diff)
+	}
+}
+
+// TestDeleteUserByID ensures the generated delete helper runs the typed PreDeleteFn.
+func TestDeleteUserByID(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("could not init db mock")
+	}
+	defer db.Close()
+
+	mock.ExpectPrepare("DELETE FROM users WHERE id = ?").
+		ExpectExec().
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	typed := false
+	repo := example.NewUserRepository(qry.Repository{DB: db, Table: "users"})
+	repo.PreDeleteFn = func(ctx context.Context, query qry.Query) error {
+		_, typed = query.(qry.TypedDeleteQuery[example.User])
+		return nil
+	}
+
+	if _, err := example.DeleteUserByID(context.Background(), repo, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !typed {
+		t.Errorf("expected PreDeleteFn to receive a typed delete query")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,112 @@
+// Command qry-gen generates qry.TypedRepository wiring for structs using `db` struct tags, so that
+// no reflection is required at runtime.
+//
+// It is intended to be used with go generate:
+//
+//	//go:generate qry-gen -type User,Order
+//
+// For each type it generates Field constants, select field references, insert and update values,
+// primary key values and condition, a repository constructor, and find and delete helpers keyed by the primary key.
+// The output is deterministic, so running it again on unchanged input produces an identical file.
+// Use -check to fail when the output file is out of date rather than writing it.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "qry-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+type config struct {
+	Dir    string
+	Types  []string
+	Output string
+	Check  bool
+}
+
+func parseFlags(args []string) (config, error) {
+	flags := flag.NewFlagSet("qry-gen", flag.ContinueOnError)
+	typesFlag := flags.String("type", "", "comma separated list of struct type names to generate for")
+	outputFlag := flags.String("output", "", "output file name. Defaults to <first type>_qry.go in the package directory")
+	dirFlag := flags.String("dir", ".", "directory of the package containing the types")
+	checkFlag := flags.Bool("check", false, "exit with an error if the output file is out of date instead of writing it")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	cfg := config{
+		Dir:    *dirFlag,
+		Output: *outputFlag,
+		Check:  *checkFlag,
+	}
+	for _, typeName := range strings.Split(*typesFlag, ",") {
+		if typeName = strings.TrimSpace(typeName); typeName != "" {
+			cfg.Types = append(cfg.Types, typeName)
+		}
+	}
+	if len(cfg.Types) == 0 {
+		return config{}, errors.New("-type is required")
+	}
+	if cfg.Output == "" {
+		cfg.Output = strings.ToLower(cfg.Types[0]) + "_qry.go"
+	}
+	if !filepath.IsAbs(cfg.Output) {
+		cfg.Output = filepath.Join(cfg.Dir, cfg.Output)
+	}
+
+	return cfg, nil
+}
+
+func run(args []string) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := parsePackage(cfg.Dir, cfg.Output)
+	if err != nil {
+		return err
+	}
+
+	structs := make([]structInfo, 0, len(cfg.Types))
+	for _, typeName := range cfg.Types {
+		info, err := pkg.structInfo(typeName)
+		if err != nil {
+			return err
+		}
+		structs = append(structs, info)
+	}
+
+	src, err := generate(pkg.Name, structs)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Check {
+		existing, err := os.ReadFile(cfg.Output)
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", cfg.Output, err)
+		}
+		if !bytes.Equal(existing, src) {
+			return fmt.Errorf("%s is out of date", cfg.Output)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(cfg.Output, src, 0o644); err != nil {
+		return fmt.Errorf("could not write %s: %w", cfg.Output, err)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_Check(t *testing.T) {
+	err := run([]string{"-dir", filepath.Join("internal", "example"), "-type", "User,Membership", "-output", "models_qry.go", "-check"})
+	if err != nil {
+		t.Errorf("expected generated code to be up to date, got: %v", err)
+	}
+}
+
+func TestRun_Idempotent(t *testing.T) {
+	dir := t.TempDir()
+	src, err := os.ReadFile(filepath.Join("internal", "example", "models.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"-dir", dir, "-type", "User,Membership"}
+	if err := run(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := os.ReadFile(filepath.Join(dir, "user_qry.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run(args); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := os.ReadFile(filepath.Join(dir, "user_qry.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("expected regenerated code to be identical")
+	}
+	if err := run(append(args, "-check")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	type def struct {
+		name   string
+		src    string
+		args   []string
+		expErr string
+	}
+	tests := []def{
+		{
+			name:   "Missing type flag",
+			src:    "package models\n",
+			expErr: "-type is required",
+		},
+		{
+			name:   "Unknown type",
+			src:    "package models\n",
+			args:   []string{"-type", "User"},
+			expErr: "struct type User not found",
+		},
+		{
+			name:   "Duplicate column",
+			src:    "package models\n\ntype User struct {\n\tA string `db:\"name\"`\n\tB string `db:\"name\"`\n}\n",
+			args:   []string{"-type", "User"},
+			expErr: `duplicate column "name"`,
+		},
+		{
+			name:   "Unknown option",
+			src:    "package models\n\ntype User struct {\n\tA string `db:\"name,unique\"`\n}\n",
+			args:   []string{"-type", "User"},
+			expErr: "unique",
+		},
+		{
+			name:   "Unexported tagged field",
+			src:    "package models\n\ntype User struct {\n\ta string `db:\"name\"`\n}\n",
+			args:   []string{"-type", "User"},
+			expErr: "tagged fields must be exported",
+		},
+		{
+			name:   "Omit empty incomparable type",
+			src:    "package models\n\ntype Tags struct {\n\tNames []string\n}\n\ntype User struct {\n\tTags Tags `db:\"tags,omitempty\"`\n}\n",
+			args:   []string{"-type", "User"},
+			expErr: "neither comparable nor nilable",
+		},
+		{
+			name:   "Embedded struct pointer",
+			src:    "package models\n\ntype Audit struct {\n\tCreatedBy string `db:\"created_by\"`\n}\n\ntype User struct {\n\t*Audit\n}\n",
+			args:   []string{"-type", "User"},
+			expErr: "embedded struct pointers are not supported",
+		},
+		{
+			name:   "Check without existing output",
+			src:    "package models\n\ntype User struct {\n\tA string `db:\"name\"`\n}\n",
+			args:   []string{"-type", "User", "-check"},
+			expErr: "could not read",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(tc.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := run(append([]string{"-dir", dir}, tc.args...))
+			if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+				t.Errorf("expected error containing %q, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRun_VersionedImport(t *testing.T) {
+	dir := t.TempDir()
+	src := "package models\n\nimport \"github.com/gofrs/uuid/v5\"\n\ntype Doc struct {\n\tID uuid.UUID `db:\"id,pk\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "models.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{"-dir", dir, "-type", "Doc"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generated, err := os.ReadFile(filepath.Join(dir, "doc_qry.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(generated), `uuid "github.com/gofrs/uuid/v5"`) {
+		t.Errorf("expected generated code to import uuid, got:\n%s", generated)
+	}
+}
+
+func TestImportName(t *testing.T) {
+	tests := map[string]string{
+		"time":                     "time",
+		"encoding/json":            "json",
+		"github.com/gofrs/uuid/v5": "uuid",
+		"gopkg.in/yaml.v3":         "yaml",
+		"github.com/user/v2ray":    "v2ray",
+	}
+	for path, exp := range tests {
+		if got := importName(path); got != exp {
+			t.Errorf("expected importName(%q) to be %q, got %q", path, exp, got)
+		}
+	}
+}
+
+func TestParamName(t *testing.T) {
+	tests := map[string]string{
+		"ID":       "id",
+		"UserID":   "userID",
+		"HTTPCode": "httpCode",
+		"Name":     "name",
+		"Type":     "typeValue",
+		"Query":    "queryValue",
+	}
+	for fieldName, exp := range tests {
+		if got := paramName(fieldName); got != exp {
+			t.Errorf("expected paramName(%q) to be %q, got %q", fieldName, exp, got)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TomWright/qry/internal/dbtag"
+)
+
+// sourcePackage is the parsed source of a package.
+type sourcePackage struct {
+	Name    string
+	structs map[string]sourceStruct
+	// types holds the types of expressions within the package, as far as they could be resolved.
+	types *types.Info
+	// typeErr is the first error found when type checking the package, if any.
+	typeErr error
+}
+
+// sourceStruct is a struct type declaration along with the imports of the file it was declared in.
+type sourceStruct struct {
+	node    *ast.StructType
+	imports map[string]string
+}
+
+// structInfo describes the columns of a struct that code is generated for.
+type structInfo struct {
+	Name   string
+	Fields []fieldInfo
+}
+
+// fieldInfo describes a struct field that is mapped to a column.
+type fieldInfo struct {
+	// Name is the name of the field, used to name the generated Field constant.
+	Name string
+	// Path is the selector used to access the field from the struct, including any embedded structs.
+	Path string
+	// Type is the source representation of the field type.
+	Type string
+	// Nilable is true if the zero value of the field type is nil.
+	Nilable bool
+	// Imports are the import paths, keyed by package name, required to reference the field type.
+	Imports map[string]string
+	Tag     dbtag.Tag
+}
+
+// parsePackage parses every non-test Go file in the given directory, other than the skipped file.
+func parsePackage(dir string, skip string) (*sourcePackage, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	skipAbs, _ := filepath.Abs(skip)
+
+	pkg := &sourcePackage{
+		structs: make(map[string]sourceStruct),
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(paths))
+
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		if abs, _ := filepath.Abs(path); abs == skipAbs {
+			continue
+		}
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s: %w", path, err)
+		}
+
+		if pkg.Name == "" {
+			pkg.Name = file.Name.Name
+		}
+		files = append(files, file)
+	}
+
+	if pkg.Name == "" {
+		return nil, fmt.Errorf("no go files found in %s", dir)
+	}
+
+	pkg.typeCheck(fset, files)
+
+	for _, file := range files {
+		imports := pkg.fileImports(file)
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+					pkg.structs[typeSpec.Name.Name] = sourceStruct{
+						node:    structType,
+						imports: imports,
+					}
+				}
+			}
+		}
+	}
+
+	return pkg, nil
+}
+
+// typeCheck resolves the types of expressions within the given files.
+// Errors do not stop the check, since the package may reference code that has not been generated yet, so
+// types are resolved wherever possible and the first error is kept to explain any that are not.
+func (pkg *sourcePackage) typeCheck(fset *token.FileSet, files []*ast.File) {
+	pkg.types = &types.Info{
+		Types:     make(map[ast.Expr]types.TypeAndValue),
+		Implicits: make(map[ast.Node]types.Object),
+	}
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			if pkg.typeErr == nil {
+				pkg.typeErr = err
+			}
+		},
+	}
+	_, _ = config.Check(pkg.Name, fset, files, pkg.types)
+}
+
+// fileImports returns the import paths of the given file keyed by the name they are referenced by.
+func (pkg *sourcePackage) fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		} else if pkgName, ok := pkg.types.Implicits[spec].(*types.PkgName); ok && pkgName.Imported().Scope().Len() > 0 {
+			// Imports that could not be resolved are given an empty package named after the last path element,
+			// so only use the name of packages that were found.
+			name = pkgName.Imported().Name()
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+// importName returns the likely name of the package with the given import path, for when the package could not
+// be resolved. Major version suffixes such as github.com/gofrs/uuid/v5 and gopkg.in/yaml.v3 are ignored.
+func importName(path string) string {
+	parts := strings.Split(path, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	if parts[0] == "gopkg.in" {
+		if i := strings.Index(name, ".v"); i > 0 && isMajorVersion(name[i+1:]) {
+			name = name[:i]
+		}
+	}
+	return name
+}
+
+// isMajorVersion returns true if the given import path element is a major version, such as v2.
+func isMajorVersion(element string) bool {
+	if len(element) < 2 || element[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(element[1:])
+	return err == nil
+}
+
+// structInfo returns the mapped columns of the named struct.
+// Embedded structs without a db tag are flattened if they are declared in the same package, and
+// ignored otherwise. Embedding a struct of the same package by pointer is an error, since the generated code
+// would not be nil-safe.
+func (pkg *sourcePackage) structInfo(name string) (structInfo, error) {
+	if _, ok := pkg.structs[name]; !ok {
+		return structInfo{}, fmt.Errorf("struct type %s not found", name)
+	}
+
+	info := structInfo{
+		Name: name,
+	}
+	if err := pkg.addFields(&info, name, "", map[string]bool{}); err != nil {
+		return structInfo{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	columns := make(map[string]bool, len(info.Fields))
+	names := make(map[string]bool, len(info.Fields))
+	for _, field := range info.Fields {
+		if columns[field.Tag.Name] {
+			return structInfo{}, fmt.Errorf("%s: duplicate column %q", name, field.Tag.Name)
+		}
+		if names[field.Name] {
+			return structInfo{}, fmt.Errorf("%s: duplicate field name %s", name, field.Name)
+		}
+		columns[field.Tag.Name] = true
+		names[field.Name] = true
+	}
+
+	return info, nil
+}
+
+func (pkg *sourcePackage) addFields(info *structInfo, structName string, pathPrefix string, visiting map[string]bool) error {
+	if visiting[structName] {
+		return fmt.Errorf("recursive embedded struct %s", structName)
+	}
+	visiting[structName] = true
+	defer delete(visiting, structName)
+
+	source := pkg.structs[structName]
+
+	for _, field := range source.node.Fields.List {
+		tagValue, hasTag := "", false
+		if field.Tag != nil {
+			rawTag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return fmt.Errorf("could not read tag %s: %w", field.Tag.Value, err)
+			}
+			tagValue, hasTag = reflect.StructTag(rawTag).Lookup(dbtag.Key)
+		}
+
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			names = append(names, ident.Name)
+		}
+
+		if len(field.Names) == 0 {
+			embeddedName := embeddedFieldName(field.Type)
+			if !hasTag {
+				if star, ok := field.Type.(*ast.StarExpr); ok {
+					if ident, ok := star.X.(*ast.Ident); ok {
+						if _, ok := pkg.structs[ident.Name]; ok {
+							return fmt.Errorf("field %s: embedded struct pointers are not supported, embed %s by value instead", embeddedName, ident.Name)
+						}
+					}
+				}
+				if ident, ok := field.Type.(*ast.Ident); ok {
+					if _, ok := pkg.structs[ident.Name]; ok {
+						if err := pkg.addFields(info, ident.Name, pathPrefix+ident.Name+".", visiting); err != nil {
+							return err
+						}
+					}
+				}
+				continue
+			}
+			names = append(names, embeddedName)
+		}
+
+		tag, ok, err := dbtag.Parse(tagValue)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", strings.Join(names, ", "), err)
+		}
+		if !ok {
+			continue
+		}
+
+		nilable := false
+		if !tag.ReadOnly && (tag.OmitEmpty || tag.AutoIncrement) {
+			// The generated code checks whether the field holds a zero value.
+			nilable, err = pkg.isNilable(field.Type)
+			if err != nil {
+				return fmt.Errorf("field %s: %w", strings.Join(names, ", "), err)
+			}
+		}
+
+		for _, name := range names {
+			if !ast.IsExported(name) {
+				return fmt.Errorf("field %s: tagged fields must be exported", name)
+			}
+			info.Fields = append(info.Fields, fieldInfo{
+				Name:    name,
+				Path:    pathPrefix + name,
+				Type:    types.ExprString(field.Type),
+				Nilable: nilable,
+				Imports: typeImports(field.Type, source.imports),
+				Tag:     tag,
+			})
+		}
+	}
+
+	return nil
+}
+
+// embeddedFieldName returns the implicit field name of an embedded field with the given type.
+func embeddedFieldName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return embeddedFieldName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	default:
+		return types.ExprString(expr)
+	}
+}
+
+// isNilable returns true if the zero value of the given type is nil, in which case the generated code compares
+// it to nil. Otherwise the type must be comparable so that it can be checked with qry.IsZero.
+func (pkg *sourcePackage) isNilable(expr ast.Expr) (bool, error) {
+	typ := pkg.types.TypeOf(expr)
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		if nilableExpr(expr) {
+			return true, nil
+		}
+		return false, fmt.Errorf("could not resolve type %s to check for a zero value: %v", types.ExprString(expr), pkg.typeErr)
+	}
+
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature, *types.Interface:
+		return true, nil
+	}
+	if !types.Comparable(typ) {
+		return false, fmt.Errorf("type %s cannot be checked for a zero value as it is neither comparable nor nilable", types.ExprString(expr))
+	}
+	return false, nil
+}
+
+// nilableExpr returns true if the given type expression is known to have a nil zero value without resolving it.
+func nilableExpr(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.StarExpr, *ast.MapType, *ast.InterfaceType, *ast.FuncType, *ast.ChanType:
+		return true
+	case *ast.ArrayType:
+		return t.Len == nil
+	case *ast.Ident:
+		return t.Name == "any"
+	default:
+		return false
+	}
+}
+
+// typeImports returns the imports referenced by the given type expression.
+func typeImports(expr ast.Expr, fileImports map[string]string) map[string]string {
+	imports := make(map[string]string)
+	ast.Inspect(expr, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := selector.X.(*ast.Ident); ok {
+			if path, ok := fileImports[ident.Name]; ok {
+				imports[ident.Name] = path
+			}
+		}
+		return false
+	})
+	return imports
+}
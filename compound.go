@@ -0,0 +1,115 @@
+package qry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeatureCompoundParentheses is support for wrapping a query within a CompoundQuery in brackets, which is
+// required when the query has its own order, limit or offset.
+const FeatureCompoundParentheses Feature = "compound_parentheses"
+
+// SetOperator combines the results of the queries within a CompoundQuery.
+type SetOperator string
+
+const (
+	// SetUnion returns the distinct rows returned by any of the queries.
+	SetUnion SetOperator = "UNION"
+	// SetUnionAll returns every row returned by any of the queries, including duplicates.
+	SetUnionAll SetOperator = "UNION ALL"
+	// SetIntersect returns the distinct rows returned by every query.
+	SetIntersect SetOperator = "INTERSECT"
+	// SetExcept returns the distinct rows returned by the first query that are not returned by the others.
+	SetExcept SetOperator = "EXCEPT"
+)
+
+// Union returns a CompoundQuery that combines the distinct results of the given queries.
+func Union(queries ...SelectQuery) CompoundQuery {
+	return CompoundQuery{Operator: SetUnion, Queries: queries}
+}
+
+// UnionAll returns a CompoundQuery that combines every result of the given queries.
+func UnionAll(queries ...SelectQuery) CompoundQuery {
+	return CompoundQuery{Operator: SetUnionAll, Queries: queries}
+}
+
+// Intersect returns a CompoundQuery that returns the results common to all the given queries.
+func Intersect(queries ...SelectQuery) CompoundQuery {
+	return CompoundQuery{Operator: SetIntersect, Queries: queries}
+}
+
+// Except returns a CompoundQuery that returns the results of the first query that are not returned by the others.
+func Except(queries ...SelectQuery) CompoundQuery {
+	return CompoundQuery{Operator: SetExcept, Queries: queries}
+}
+
+// CompoundQuery is a Query that combines the results of many SelectQuery values using a SetOperator.
+// Each query must select the same number of fields.
+//
+// OrderBy, Limit and Offset apply to the combined results. A query within the CompoundQuery that has its own
+// order, limit or offset is wrapped in brackets, which requires FeatureCompoundParentheses.
+//
+// Use TypedRepository.QueryCompound to scan the results into a TypedRepository type.
+type CompoundQuery struct {
+	Operator SetOperator
+	Queries  []SelectQuery
+	OrderBy  []OrderBy
+	Limit    int64
+	Offset   int64
+}
+
+func (query CompoundQuery) Build() (string, []any) {
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
+
+func (query CompoundQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query CompoundQuery) returnsRows() {}
+
+func (query CompoundQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	if len(query.Queries) == 0 {
+		return "", nil, fmt.Errorf("%w: compound query requires at least one query", ErrInvalidQuery)
+	}
+
+	operator := query.Operator
+	if operator == "" {
+		operator = SetUnion
+	}
+
+	parts := make([]string, 0, len(query.Queries))
+	args := make([]any, 0)
+	for _, part := range query.Queries {
+		partStmt, partArgs, err := part.buildDialect(dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(part.OrderBy) > 0 || part.Limit > 0 || part.Offset > 0 {
+			if err := requireFeature(dialect, FeatureCompoundParentheses); err != nil {
+				return "", nil, err
+			}
+			partStmt = fmt.Sprintf("(%s)", partStmt)
+		}
+		parts = append(parts, partStmt)
+		args = append(args, partArgs...)
+	}
+
+	stmt := strings.Join(parts, fmt.Sprintf(" %s ", operator))
+
+	limitPrefix, limitSuffix := dialect.Paginate(query.Limit, query.Offset, len(query.OrderBy) > 0)
+	if limitPrefix != "" {
+		// The limit must be applied to the combined results rather than to the first query,
+		// so select from them as a subquery.
+		stmt = fmt.Sprintf("SELECT %s* FROM (%s) AS qry_compound", limitPrefix, stmt)
+	}
+
+	if len(query.OrderBy) > 0 {
+		stmt += fmt.Sprintf(" ORDER BY %s", buildOrderBy(query.OrderBy, dialect))
+	}
+
+	stmt += limitSuffix
+
+	return stmt, args, nil
+}
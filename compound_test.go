@@ -0,0 +1,233 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestCompoundQuery_BuildDialect(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.CompoundQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	comments := qry.SelectQuery{
+		Fields:    []qry.Field{"id", "created_at"},
+		Table:     "comments",
+		Condition: qry.Equal("user_id", 1),
+	}
+	likes := qry.SelectQuery{
+		Fields:    []qry.Field{"id", "created_at"},
+		Table:     "likes",
+		Condition: qry.Equal("user_id", 1),
+	}
+	latestLike := likes
+	latestLike.OrderBy = []qry.OrderBy{{Field: "created_at", Direction: qry.Descending}}
+	latestLike.Limit = 1
+
+	newest := []qry.OrderBy{{Field: "created_at", Direction: qry.Descending}}
+
+	tests := []def{
+		{
+			name:    "Union",
+			query:   qry.Union(comments, likes),
+			dialect: qry.MySQL,
+			expStmt: "SELECT id, created_at FROM comments WHERE user_id = ? UNION SELECT id, created_at FROM likes WHERE user_id = ?",
+			expArgs: []any{1, 1},
+		},
+		{
+			name: "Union all with order and limit",
+			query: qry.CompoundQuery{
+				Operator: qry.SetUnionAll,
+				Queries:  []qry.SelectQuery{comments, likes},
+				OrderBy:  newest,
+				Limit:    10,
+				Offset:   20,
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id, created_at FROM comments WHERE user_id = $1 UNION ALL SELECT id, created_at FROM likes WHERE user_id = $2 ORDER BY created_at DESC LIMIT 10 OFFSET 20",
+			expArgs: []any{1, 1},
+		},
+		{
+			name: "SQL Server limit",
+			query: qry.CompoundQuery{
+				Operator: qry.SetUnionAll,
+				Queries:  []qry.SelectQuery{comments, likes},
+				OrderBy:  newest,
+				Limit:    10,
+			},
+			dialect: qry.SQLServer,
+			expStmt: "SELECT TOP 10 * FROM (SELECT id, created_at FROM comments WHERE user_id = @p1 UNION ALL SELECT id, created_at FROM likes WHERE user_id = @p2) AS qry_compound ORDER BY created_at DESC",
+			expArgs: []any{1, 1},
+		},
+		{
+			name:    "Intersect",
+			query:   qry.Intersect(comments, likes),
+			dialect: qry.SQLite,
+			expStmt: "SELECT id, created_at FROM comments WHERE user_id = ? INTERSECT SELECT id, created_at FROM likes WHERE user_id = ?",
+			expArgs: []any{1, 1},
+		},
+		{
+			name:    "Except with limited query",
+			query:   qry.Except(comments, latestLike),
+			dialect: qry.MySQL,
+			expStmt: "SELECT id, created_at FROM comments WHERE user_id = ? EXCEPT (SELECT id, created_at FROM likes WHERE user_id = ? ORDER BY created_at DESC LIMIT 1)",
+			expArgs: []any{1, 1},
+		},
+		{
+			name:    "SQLite except with limited query",
+			query:   qry.Except(comments, latestLike),
+			dialect: qry.SQLite,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name:    "No queries",
+			query:   qry.Union(),
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func TestRepository_Query_Compound(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users WHERE name = ? UNION SELECT id, name FROM admins WHERE name = ? ORDER BY id ASC").
+		WithArgs("Tom", "Tom").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom")).
+		RowsWillBeClosed()
+
+	repo := qry.Repository{
+		DB:                   db,
+		Table:                "users",
+		StandardSelectFields: []qry.Field{"id", "name"},
+	}
+
+	rows, err := repo.Query(context.Background(), qry.CompoundQuery{
+		Queries: []qry.SelectQuery{
+			{Condition: qry.Equal("name", "Tom")},
+			{Table: "admins", Condition: qry.Equal("name", "Tom")},
+		},
+		OrderBy: []qry.OrderBy{{Field: "id", Direction: qry.Ascending}},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	got, err := scanRowsToMapInterface([]qry.Field{"id", "name"}, rows)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiff(t, []map[string]interface{}{{"id": int64(1), "name": "Tom"}}, got)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTypedRepository_Query_Compound(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM (SELECT id, name FROM users UNION ALL SELECT id, name FROM admins) AS people ORDER BY name ASC LIMIT 2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(2), "Jim").AddRow(int64(1), "Tom"))
+
+	repo := newModelRepo(db)
+
+	got, err := repo.QueryFn(context.Background(), func(query *qry.TypedSelectQuery[model]) {
+		query.From = qry.As(qry.UnionAll(
+			qry.SelectQuery{Fields: []qry.Field{"id", "name"}, Table: "users"},
+			qry.SelectQuery{Fields: []qry.Field{"id", "name"}, Table: "admins"},
+		), "people")
+		query.OrderBy = []qry.OrderBy{{Field: "name", Direction: qry.Ascending}}
+		query.Limit = 2
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiff(t, []*model{{ID: 2, Name: "Jim"}, {ID: 1, Name: "Tom"}}, got)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTypedRepository_QueryCompound(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users UNION SELECT id, name FROM admins ORDER BY name ASC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(2), "Jim").AddRow(int64(1), "Tom"))
+
+	repo := newModelRepo(db)
+
+	query := qry.Union(qry.SelectQuery{}, qry.SelectQuery{Table: "admins"})
+	query.OrderBy = []qry.OrderBy{{Field: "name", Direction: qry.Ascending}}
+
+	got, err := repo.QueryCompound(context.Background(), query)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiff(t, []*model{{ID: 2, Name: "Jim"}, {ID: 1, Name: "Tom"}}, got)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -20,19 +20,27 @@ type ConditionGroup struct {
 // Build returns an SQL statement and the related args.
 // The statement is already wrapped in brackets.
 func (group *ConditionGroup) Build() (string, []any) {
+	stmt, args, _ := group.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (group *ConditionGroup) buildDialect(dialect Dialect) (string, []any, error) {
 	if group == nil {
-		return "", make([]any, 0)
+		return "", make([]any, 0), nil
 	}
 	parts := make([]string, 0)
 	args := make([]any, 0)
 
 	if len(group.Conditions) == 0 {
-		return "", args
+		return "", args, nil
 	}
 
 	if len(group.Conditions) > 0 {
 		for _, cs := range group.Conditions {
-			part, partArgs := cs.Build()
+			part, partArgs, err := buildPart(cs, dialect)
+			if err != nil {
+				return "", nil, err
+			}
 			parts = append(parts, part)
 			args = append(args, partArgs...)
 		}
@@ -43,11 +51,12 @@ func (group *ConditionGroup) Build() (string, []any) {
 		sep = " OR "
 	}
 
-	return fmt.Sprintf("(%s)", strings.Join(parts, sep)), args
+	return fmt.Sprintf("(%s)", strings.Join(parts, sep)), args, nil
 }
 
 // SimpleCondition is a Condition that can be used to make a basic comparison.
 // E.g. user_id = "123"
+// A Value that is a SelectQuery is compared as a subquery.
 type SimpleCondition struct {
 	Field      Field
 	Value      any
@@ -56,12 +65,21 @@ type SimpleCondition struct {
 
 // Build returns an SQL statement and the related args.
 func (query *SimpleCondition) Build() (string, []any) {
-	stmt := fmt.Sprintf("%s %s ?", query.Field, query.Comparison)
-	args := []any{query.Value}
+	stmt, args, _ := query.buildDialect(MySQL)
 	return stmt, args
 }
 
+func (query *SimpleCondition) buildDialect(dialect Dialect) (string, []any, error) {
+	valueStmt, args, err := buildValue(query.Value, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s %s", identifier(dialect, query.Field), query.Comparison, valueStmt), args, nil
+}
+
 // RawCondition is a Condition that can be used to make more complex comparisons.
+// Each ? outside of a quoted string or identifier is a placeholder for one of the Args. Use ?? for a literal ?,
+// such as the Postgres jsonb ? operator.
 type RawCondition struct {
 	SQL  string
 	Args []any
@@ -71,3 +89,44 @@ type RawCondition struct {
 func (query *RawCondition) Build() (string, []any) {
 	return query.SQL, query.Args
 }
+
+// fieldCondition is a Condition that references a single field, so that the field can be quoted by the Dialect.
+type fieldCondition struct {
+	Field Field
+	// SQL is the condition with %s in place of the field.
+	SQL  string
+	Args []any
+}
+
+// Build returns an SQL statement and the related args.
+func (query *fieldCondition) Build() (string, []any) {
+	stmt, args, _ := query.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (query *fieldCondition) buildDialect(dialect Dialect) (string, []any, error) {
+	return fmt.Sprintf(query.SQL, identifier(dialect, query.Field)), query.Args, nil
+}
+
+// NotCondition is a Condition that negates another Condition.
+type NotCondition struct {
+	Condition Condition
+}
+
+// Build returns an SQL statement and the related args.
+// An empty inner Condition results in an empty statement.
+func (query *NotCondition) Build() (string, []any) {
+	stmt, args, _ := query.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (query *NotCondition) buildDialect(dialect Dialect) (string, []any, error) {
+	stmt, args, err := buildPart(query.Condition, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	if stmt == "" {
+		return "", args, nil
+	}
+	return fmt.Sprintf("NOT (%s)", stmt), args, nil
+}
@@ -0,0 +1,185 @@
+package qry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// countQuery is a Query that counts the rows matched by a SelectQuery, ignoring its order, pagination and locking.
+// Grouped and distinct queries are counted using a subquery so that each group or distinct row is counted once.
+type countQuery struct {
+	query SelectQuery
+}
+
+func (query countQuery) Build() (string, []any) {
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
+
+func (query countQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query countQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	inner := query.query
+	inner.OrderBy = nil
+	inner.Limit = 0
+	inner.Offset = 0
+	inner.Lock = nil
+
+	if len(inner.GroupBy) == 0 && !inner.Distinct && len(inner.DistinctOn) == 0 {
+		inner.Fields = []Field{Count("*")}
+		return inner.buildDialect(dialect)
+	}
+
+	outer := SelectQuery{
+		Fields: []Field{Count("*")},
+		From:   As(inner, "qry_count"),
+	}
+	return outer.buildDialect(dialect)
+}
+
+// Count returns the number of rows in the Repository Table that match the given Condition.
+// A nil Condition counts every row.
+func (repo Repository) Count(ctx context.Context, condition Condition) (int64, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Count")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	return repo.count(ctx, SelectQuery{
+		Condition: condition,
+	})
+}
+
+// Exists returns true if any row in the Repository Table matches the given Condition.
+func (repo Repository) Exists(ctx context.Context, condition Condition) (bool, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Exists")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	return repo.exists(ctx, repo.existsQuery(condition))
+}
+
+// count returns the number of rows matched by the given select query, ignoring its order and pagination.
+func (repo Repository) count(ctx context.Context, query SelectQuery) (int64, error) {
+	countQuery := countQuery{
+		query: repo.prepareSelectQuery(query),
+	}
+
+	if repo.PreSelectFn != nil {
+		if err := repo.PreSelectFn(ctx, countQuery); err != nil {
+			return 0, fmt.Errorf("pre select hook failed: %w", err)
+		}
+	}
+
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := repo.queryRows(ctx, countQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("could not read rows: %w", err)
+		}
+		return 0, sql.ErrNoRows
+	}
+
+	var count int64
+	if err := rows.Scan(&count); err != nil {
+		return 0, fmt.Errorf("could not scan row: %w", err)
+	}
+
+	return count, nil
+}
+
+// existsQuery returns a query that selects a single row from the Repository Table that matches the given Condition.
+func (repo Repository) existsQuery(condition Condition) SelectQuery {
+	return repo.prepareSelectQuery(SelectQuery{
+		Fields:    []Field{"1"},
+		Condition: condition,
+		Limit:     1,
+	})
+}
+
+// exists returns true if the given query returns a row.
+func (repo Repository) exists(ctx context.Context, query SelectQuery) (bool, error) {
+	if repo.PreSelectFn != nil {
+		if err := repo.PreSelectFn(ctx, query); err != nil {
+			return false, fmt.Errorf("pre select hook failed: %w", err)
+		}
+	}
+
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := repo.queryRows(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	defer rows.Close()
+
+	if rows.Next() {
+		return true, nil
+	}
+	if err := rows.Err(); err != nil {
+		return false, fmt.Errorf("could not read rows: %w", err)
+	}
+	return false, nil
+}
+
+// Count returns the number of rows in the Repository Table that match the given Condition.
+// A nil Condition counts every row.
+func (repo TypedRepository[T]) Count(ctx context.Context, condition Condition) (int64, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Count")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query := repo.SelectQuery()
+	query.Condition = condition
+	return repo.count(ctx, query)
+}
+
+// Exists returns true if any row in the Repository Table matches the given Condition.
+func (repo TypedRepository[T]) Exists(ctx context.Context, condition Condition) (bool, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Exists")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query := repo.Repository.existsQuery(condition)
+
+	if repo.PreSelectFn != nil {
+		if err := repo.PreSelectFn(ctx, query); err != nil {
+			return false, fmt.Errorf("pre select hook failed: %w", err)
+		}
+	}
+
+	return repo.Repository.exists(ctx, query)
+}
+
+// count returns the number of rows matched by the given select query, ignoring its order and pagination.
+func (repo TypedRepository[T]) count(ctx context.Context, query TypedSelectQuery[T]) (int64, error) {
+	query = repo.prepareSelectQuery(query)
+
+	if repo.PreSelectFn != nil {
+		if err := repo.PreSelectFn(ctx, countQuery{query: query.Prepare()}); err != nil {
+			return 0, fmt.Errorf("pre select hook failed: %w", err)
+		}
+	}
+
+	return repo.Repository.count(ctx, query.Prepare())
+}
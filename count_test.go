@@ -0,0 +1,188 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestRepository_Count(t *testing.T) {
+	type def struct {
+		name      string
+		dialect   qry.Dialect
+		condition qry.Condition
+		exp       int64
+		mockFn    func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name: "Every row",
+			exp:  3,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM users").
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(3)))
+			},
+		},
+		{
+			name:      "Condition",
+			dialect:   qry.Postgres,
+			condition: qry.Equal("name", "Tom"),
+			exp:       1,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM users WHERE name = $1").
+					WithArgs("Tom").
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(1)))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := qry.Repository{
+				DB:      db,
+				Table:   "users",
+				Dialect: tc.dialect,
+			}
+
+			got, err := repo.Count(context.Background(), tc.condition)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRepository_Exists(t *testing.T) {
+	type def struct {
+		name      string
+		dialect   qry.Dialect
+		condition qry.Condition
+		exp       bool
+		mockFn    func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name:      "Exists",
+			condition: qry.Equal("name", "Tom"),
+			exp:       true,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT 1 FROM users WHERE name = ? LIMIT 1").
+					WithArgs("Tom").
+					WillReturnRows(sqlmock.NewRows([]string{"1"}).AddRow(1)).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name:      "Does not exist",
+			dialect:   qry.SQLServer,
+			condition: qry.Equal("name", "Tom"),
+			exp:       false,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT TOP 1 1 FROM users WHERE name = @p1").
+					WithArgs("Tom").
+					WillReturnRows(sqlmock.NewRows([]string{"1"})).
+					RowsWillBeClosed()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := qry.Repository{
+				DB:      db,
+				Table:   "users",
+				Dialect: tc.dialect,
+			}
+
+			got, err := repo.Exists(context.Background(), tc.condition)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTypedRepository_CountExists_Hooks(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT COUNT(*) FROM users WHERE name = ?").
+		WithArgs("Tom").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(2)))
+
+	hookCalls := 0
+	repo := newModelRepo(db)
+	repo.PreSelectFn = func(ctx context.Context, query qry.Query) error {
+		hookCalls++
+		if hookCalls > 1 {
+			return errDenied
+		}
+		return nil
+	}
+
+	count, err := repo.Count(context.Background(), qry.Equal("name", "Tom"))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiff(t, int64(2), count)
+
+	if _, err := repo.Exists(context.Background(), qry.Equal("name", "Tom")); !errors.Is(err, errDenied) {
+		t.Errorf("expected error %v, got %v", errDenied, err)
+	}
+
+	checkDiff(t, 2, hookCalls)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
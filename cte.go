@@ -0,0 +1,97 @@
+package qry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeatureWithRecursive is support for the RECURSIVE keyword in a WITH clause.
+// Dialects without it, such as SQL Server, allow any CTE to be recursive without the keyword.
+const FeatureWithRecursive Feature = "with_recursive"
+
+// CTE is a common table expression, a named query that can be referenced by the query it is attached to.
+type CTE struct {
+	Name string
+	// Columns optionally names the columns of the results of the Query.
+	Columns []Field
+	Query   Query
+	// Recursive is true if the Query references the CTE itself.
+	// The RECURSIVE keyword is added to the WITH clause if any CTE is recursive.
+	Recursive bool
+}
+
+// With returns a CTE with the given name and query.
+func With(name string, query Query, columns ...Field) CTE {
+	return CTE{
+		Name:    name,
+		Columns: columns,
+		Query:   query,
+	}
+}
+
+// WithRecursive returns a recursive CTE with the given name and query.
+// The query is usually a UNION ALL of a base query and a query that references the CTE.
+func WithRecursive(name string, query Query, columns ...Field) CTE {
+	cte := With(name, query, columns...)
+	cte.Recursive = true
+	return cte
+}
+
+// buildWith returns the WITH clause for the given CTEs, including a trailing space.
+func buildWith(ctes []CTE, dialect Dialect) (string, []any, error) {
+	args := make([]any, 0)
+	if len(ctes) == 0 {
+		return "", args, nil
+	}
+
+	recursive := false
+	parts := make([]string, 0, len(ctes))
+	for _, cte := range ctes {
+		if cte.Name == "" {
+			return "", nil, fmt.Errorf("%w: CTE requires a name", ErrInvalidQuery)
+		}
+		if cte.Query == nil {
+			return "", nil, fmt.Errorf("%w: CTE %s requires a query", ErrInvalidQuery, cte.Name)
+		}
+
+		stmt, cteArgs, err := buildPart(cte.Query, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+
+		name := identifier(dialect, cte.Name)
+		if len(cte.Columns) > 0 {
+			name += fmt.Sprintf("(%s)", identifiers(dialect, cte.Columns))
+		}
+
+		parts = append(parts, fmt.Sprintf("%s AS (%s)", name, stmt))
+		args = append(args, cteArgs...)
+		recursive = recursive || cte.Recursive
+	}
+
+	keyword := "WITH "
+	if recursive && dialect.Supports(FeatureWithRecursive) {
+		keyword = "WITH RECURSIVE "
+	}
+
+	return keyword + strings.Join(parts, ", ") + " ", args, nil
+}
+
+// RawQuery is a Query made up of the given SQL and args, for statements that cannot otherwise be built.
+// It can be used anywhere a subquery is accepted.
+// Placeholders must be written as ? and are rebound for the Dialect of the query it is used within.
+type RawQuery struct {
+	SQL  string
+	Args []any
+}
+
+// Build returns an SQL statement and the related args.
+func (query RawQuery) Build() (string, []any) {
+	return query.SQL, query.Args
+}
+
+func (query RawQuery) returnsRows() {}
+
+func (query RawQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	return query.SQL, query.Args, nil
+}
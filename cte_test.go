@@ -0,0 +1,128 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestCTE(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	hierarchy := qry.WithRecursive("reports", qry.RawQuery{
+		SQL:  "SELECT id, manager_id FROM employees WHERE id = ? UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN reports r ON e.manager_id = r.id",
+		Args: []any{1},
+	}, "id", "manager_id")
+
+	paidOrders := qry.With("paid_orders", qry.SelectQuery{
+		Fields:    []qry.Field{"user_id"},
+		Table:     "orders",
+		Condition: qry.Equal("status", "paid"),
+	})
+
+	tests := []def{
+		{
+			name: "Select with recursive CTE",
+			query: qry.SelectQuery{
+				With:      []qry.CTE{hierarchy},
+				Fields:    []qry.Field{"id"},
+				Table:     "reports",
+				Condition: qry.NotEqual("id", 1),
+			},
+			dialect: qry.Postgres,
+			expStmt: "WITH RECURSIVE reports(id, manager_id) AS (SELECT id, manager_id FROM employees WHERE id = $1 UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN reports r ON e.manager_id = r.id) SELECT id FROM reports WHERE id != $2",
+			expArgs: []any{1, 1},
+		},
+		{
+			name: "SQL Server recursive CTE",
+			query: qry.SelectQuery{
+				With:   []qry.CTE{hierarchy},
+				Fields: []qry.Field{"id"},
+				Table:  "reports",
+				Limit:  10,
+			},
+			dialect: qry.SQLServer,
+			expStmt: "WITH reports(id, manager_id) AS (SELECT id, manager_id FROM employees WHERE id = @p1 UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN reports r ON e.manager_id = r.id) SELECT TOP 10 id FROM reports",
+			expArgs: []any{1},
+		},
+		{
+			name: "Multiple CTEs",
+			query: qry.SelectQuery{
+				With:      []qry.CTE{paidOrders, hierarchy},
+				Fields:    []qry.Field{"id"},
+				Table:     "reports",
+				Condition: qry.In("id", qry.SelectQuery{Fields: []qry.Field{"user_id"}, Table: "paid_orders"}),
+			},
+			dialect: qry.MySQL,
+			expStmt: "WITH RECURSIVE paid_orders AS (SELECT user_id FROM orders WHERE status = ?), reports(id, manager_id) AS (SELECT id, manager_id FROM employees WHERE id = ? UNION ALL SELECT e.id, e.manager_id FROM employees e JOIN reports r ON e.manager_id = r.id) SELECT id FROM reports WHERE id IN (SELECT user_id FROM paid_orders)",
+			expArgs: []any{"paid", 1},
+		},
+		{
+			name: "Update with CTE",
+			query: qry.UpdateQuery{
+				With:      []qry.CTE{paidOrders},
+				Table:     "users",
+				Values:    map[qry.Field]any{"tier": "gold"},
+				Condition: qry.In("id", qry.SelectQuery{Fields: []qry.Field{"user_id"}, Table: "paid_orders"}),
+			},
+			dialect: qry.Postgres,
+			expStmt: "WITH paid_orders AS (SELECT user_id FROM orders WHERE status = $1) UPDATE users SET tier = $2 WHERE id IN (SELECT user_id FROM paid_orders)",
+			expArgs: []any{"paid", "gold"},
+		},
+		{
+			name: "Delete with CTE",
+			query: qry.DeleteQuery{
+				With:      []qry.CTE{paidOrders},
+				Table:     "users",
+				Condition: qry.And(qry.Equal("active", false), qry.NotIn("id", qry.SelectQuery{Fields: []qry.Field{"user_id"}, Table: "paid_orders"})),
+			},
+			dialect: qry.SQLite,
+			expStmt: "WITH paid_orders AS (SELECT user_id FROM orders WHERE status = ?) DELETE FROM users WHERE (active = ? AND id NOT IN (SELECT user_id FROM paid_orders))",
+			expArgs: []any{"paid", false},
+		},
+		{
+			name: "Missing name",
+			query: qry.SelectQuery{
+				With:   []qry.CTE{qry.With("", qry.RawQuery{SQL: "SELECT 1"})},
+				Fields: []qry.Field{"id"},
+				Table:  "users",
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package qry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Cursor streams the results of a TypedRepository query, scanning a single row at a time rather than
+// loading every row into memory.
+//
+// Call Next to advance to each row and Value to get the scanned result. Once Next returns false, Err
+// returns any error that stopped the iteration, including errors reported by the database after the last row.
+// The underlying rows are closed as soon as iteration stops, but Close should always be deferred in case
+// iteration is abandoned early.
+type Cursor[T any] struct {
+	ctx   context.Context
+	repo  TypedRepository[T]
+	query Query
+	// references returns the fields of a target that each row is scanned into.
+	references func(target *T) []any
+	rows       *sql.Rows
+	// cancel releases the QueryTimeout once the rows are closed.
+	cancel context.CancelFunc
+	// spans are the Tracer spans that end once the rows are closed, innermost first.
+	spans []trace.Span
+	value *T
+	err   error
+}
+
+// Next scans the next row, returning false once there are no rows left or an error occurs.
+func (cursor *Cursor[T]) Next() bool {
+	if cursor.rows == nil || cursor.err != nil {
+		return false
+	}
+
+	if !cursor.rows.Next() {
+		if err := cursor.rows.Err(); err != nil {
+			cursor.err = fmt.Errorf("could not read rows: %w", err)
+		}
+		_ = cursor.Close()
+		return false
+	}
+
+	value, err := cursor.repo.ScanRow(cursor.ctx, cursor.query, cursor.rows, cursor.references)
+	if err != nil {
+		cursor.value = nil
+		cursor.err = err
+		_ = cursor.Close()
+		return false
+	}
+
+	cursor.value = value
+	return true
+}
+
+// Value returns the result scanned by the last call to Next.
+func (cursor *Cursor[T]) Value() *T {
+	return cursor.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (cursor *Cursor[T]) Err() error {
+	return cursor.err
+}
+
+// all returns every remaining result, closing the cursor once done.
+func (cursor *Cursor[T]) all() ([]*T, error) {
+	defer cursor.Close()
+	results := make([]*T, 0)
+
+	for cursor.Next() {
+		results = append(results, cursor.Value())
+	}
+
+	return results, cursor.Err()
+}
+
+// Close closes the underlying rows. It is safe to call Close more than once.
+func (cursor *Cursor[T]) Close() error {
+	if cursor.rows == nil {
+		return nil
+	}
+	rows := cursor.rows
+	cursor.rows = nil
+	defer cursor.endSpans()
+	defer cursor.cancel()
+	return rows.Close()
+}
+
+func (cursor *Cursor[T]) endSpans() {
+	for _, span := range cursor.spans {
+		span.End()
+	}
+	cursor.spans = nil
+}
+
+// endSpanOnClose ends the span once the cursor is closed, or straight away if the cursor could not be opened.
+// The span is nil when there is no Tracer.
+func endSpanOnClose[T any](span trace.Span, cursor *Cursor[T], err error) (*Cursor[T], error) {
+	if span == nil {
+		return cursor, err
+	}
+	if err != nil {
+		span.End()
+		return nil, err
+	}
+	cursor.spans = append(cursor.spans, span)
+	return cursor, nil
+}
+
+func (repo TypedRepository[T]) CursorFn(ctx context.Context, queryFn func(*TypedSelectQuery[T])) (*Cursor[T], error) {
+	var span trace.Span
+	if repo.Tracer != nil {
+		ctx, span = repo.Tracer.Start(ctx, "CursorFn")
+	}
+
+	query := repo.SelectQuery()
+	queryFn(&query)
+	cursor, err := repo.Cursor(ctx, query)
+	return endSpanOnClose(span, cursor, err)
+}
+
+// Cursor executes the given query and returns a Cursor that scans each row as it is requested.
+// The caller must close the Cursor once it is done with it, which also ends its Tracer span.
+func (repo TypedRepository[T]) Cursor(ctx context.Context, query TypedSelectQuery[T]) (*Cursor[T], error) {
+	var span trace.Span
+	if repo.Tracer != nil {
+		ctx, span = repo.Tracer.Start(ctx, "Cursor")
+	}
+
+	query = repo.prepareSelectQuery(query)
+
+	cursor, err := repo.openCursor(ctx, query, query.Prepare(), query.FieldReferences)
+	return endSpanOnClose(span, cursor, err)
+}
+
+// openCursor calls the PreSelectFn hook with query before executing rowsQuery, and returns a Cursor that scans
+// each of the rows using references.
+func (repo TypedRepository[T]) openCursor(ctx context.Context, query Query, rowsQuery Query, references func(*T) []any) (*Cursor[T], error) {
+	if repo.PreSelectFn != nil {
+		if err := repo.PreSelectFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre select hook failed: %w", err)
+		}
+	}
+
+	// The timeout is released by Close, as the rows are read after the Cursor is returned.
+	ctx, cancel := repo.withQueryTimeout(ctx)
+
+	rows, err := repo.Repository.Query(ctx, rowsQuery)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &Cursor[T]{
+		ctx:        ctx,
+		repo:       repo,
+		query:      query,
+		references: references,
+		rows:       rows,
+		cancel:     cancel,
+	}, nil
+}
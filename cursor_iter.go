@@ -0,0 +1,65 @@
+//go:build go1.23
+
+package qry
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns an iterator over the remaining results of the Cursor, for use with range.
+// The Cursor is closed once the loop completes or is exited early.
+// If an error occurs it is yielded with a nil result and iteration stops.
+func (cursor *Cursor[T]) All() iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		defer cursor.Close()
+
+		for cursor.Next() {
+			if !yield(cursor.Value(), nil) {
+				return
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// IterFn returns an iterator over the results of the query built by queryFn, for use with range.
+// See Iter.
+func (repo TypedRepository[T]) IterFn(ctx context.Context, queryFn func(*TypedSelectQuery[T])) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		ctx := ctx
+		if repo.Tracer != nil {
+			spanCtx, span := repo.Tracer.Start(ctx, "IterFn")
+			ctx = spanCtx
+			defer span.End()
+		}
+
+		query := repo.SelectQuery()
+		queryFn(&query)
+		repo.Iter(ctx, query)(yield)
+	}
+}
+
+// Iter returns an iterator over the results of the given query, for use with range.
+// The query is executed when the loop starts and each row is scanned as it is reached.
+// The rows are closed once the loop completes or is exited early.
+// If an error occurs it is yielded with a nil result and iteration stops.
+func (repo TypedRepository[T]) Iter(ctx context.Context, query TypedSelectQuery[T]) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		ctx := ctx
+		if repo.Tracer != nil {
+			spanCtx, span := repo.Tracer.Start(ctx, "Iter")
+			ctx = spanCtx
+			defer span.End()
+		}
+
+		cursor, err := repo.Cursor(ctx, query)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		cursor.All()(yield)
+	}
+}
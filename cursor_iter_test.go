@@ -0,0 +1,126 @@
+//go:build go1.23
+
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestTypedRepository_Iter(t *testing.T) {
+	errRow := errors.New("connection lost")
+
+	type def struct {
+		name   string
+		take   int
+		exp    []*model
+		expErr error
+		mockFn func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name: "Every row",
+			take: -1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+				{ID: 2, Name: "Jim"},
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name: "Rows error",
+			take: -1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+			},
+			expErr: errRow,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim").
+							RowError(1, errRow),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name: "Break early",
+			take: 1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name:   "Query error",
+			take:   -1,
+			exp:    []*model{},
+			expErr: errRow,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnError(errRow)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			got := make([]*model, 0)
+			var gotErr error
+			for result, err := range newModelRepo(db).IterFn(context.Background(), func(query *qry.TypedSelectQuery[model]) {}) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, result)
+				if len(got) == tc.take {
+					break
+				}
+			}
+
+			if !errors.Is(gotErr, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, gotErr)
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
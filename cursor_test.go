@@ -0,0 +1,219 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func newModelRepo(db qry.DBTX) qry.TypedRepository[model] {
+	return qry.TypedRepository[model]{
+		Repository: qry.Repository{
+			DB:                   db,
+			Table:                "users",
+			StandardSelectFields: []qry.Field{"id", "name"},
+		},
+		StandardSelectFieldReferences: func(target *model) []any {
+			return []any{
+				&target.ID,
+				&target.Name,
+			}
+		},
+	}
+}
+
+func TestTypedRepository_Cursor(t *testing.T) {
+	errRow := errors.New("connection lost")
+
+	type def struct {
+		name   string
+		take   int
+		exp    []*model
+		expErr error
+		mockFn func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name: "Every row",
+			take: -1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+				{ID: 2, Name: "Jim"},
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name: "Rows error",
+			take: -1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+			},
+			expErr: errRow,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim").
+							RowError(1, errRow),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name: "Closed early",
+			take: 1,
+			exp: []*model{
+				{ID: 1, Name: "Tom"},
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom").
+							AddRow(int64(2), "Jim"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			cursor, err := newModelRepo(db).CursorFn(context.Background(), func(query *qry.TypedSelectQuery[model]) {})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			got := make([]*model, 0)
+			for (tc.take < 0 || len(got) < tc.take) && cursor.Next() {
+				got = append(got, cursor.Value())
+			}
+
+			if err := cursor.Close(); err != nil {
+				t.Errorf("unexpected close error: %v", err)
+			}
+
+			if !errors.Is(cursor.Err(), tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, cursor.Err())
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTypedRepository_Query_RowsError(t *testing.T) {
+	errRow := errors.New("connection lost")
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "Tom").
+				AddRow(int64(2), "Jim").
+				RowError(1, errRow),
+		).
+		RowsWillBeClosed()
+
+	got, err := newModelRepo(db).QueryFn(context.Background(), func(query *qry.TypedSelectQuery[model]) {})
+	if !errors.Is(err, errRow) {
+		t.Errorf("expected error %v, got %v", errRow, err)
+	}
+
+	checkDiff(t, []*model{{ID: 1, Name: "Tom"}}, got)
+}
+
+// recordingTracer records the names of the spans it starts and ends.
+type recordingTracer struct {
+	ended *[]string
+}
+
+func (tracer recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := recordingSpan{Span: trace.SpanFromContext(ctx), name: name, ended: tracer.ended}
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	trace.Span
+	name  string
+	ended *[]string
+}
+
+func (span recordingSpan) End(...trace.SpanEndOption) {
+	*span.ended = append(*span.ended, span.name)
+}
+
+func TestTypedRepository_CursorFn_Span(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom")).
+		RowsWillBeClosed()
+
+	ended := make([]string, 0)
+	repo := newModelRepo(db)
+	repo.Tracer = recordingTracer{ended: &ended}
+
+	cursor, err := repo.CursorFn(context.Background(), func(query *qry.TypedSelectQuery[model]) {})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiffMsg(t, []string{"Query"}, ended, "spans ended before close")
+
+	for cursor.Next() {
+	}
+	if err := cursor.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	checkDiffMsg(t, []string{"Query", "Cursor", "CursorFn"}, ended, "spans ended after close")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -9,36 +9,97 @@ func Delete() DeleteQuery {
 }
 
 type DeleteQuery struct {
-	Table     string
+	// With is the common table expressions available to the query.
+	With  []CTE
+	Table string
+	// Join is the tables joined to Table, whose fields can be used in Condition.
+	// Dialects without FeatureMutationJoin render inner joins with FeatureDeleteUsing.
+	Join      []Join
 	Condition Condition
 	Limit     int64
 	Offset    int64
+	// Returning is the fields returned for each deleted row.
+	Returning []Field
 }
 
 func (query DeleteQuery) Build() (string, []any) {
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
+
+func (query DeleteQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query DeleteQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	if query.Limit > 0 || query.Offset > 0 {
+		if err := requireFeature(dialect, FeatureMutationLimit); err != nil {
+			return "", nil, err
+		}
+	}
+
+	withStmt, args, err := buildWith(query.With, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	target, joinStmt, usingStmt, condition := "", "", "", query.Condition
+	switch {
+	case len(query.Join) == 0:
+	case dialect.Supports(FeatureMutationJoin):
+		if query.Limit > 0 || query.Offset > 0 {
+			return "", nil, fmt.Errorf("%w: a delete with joins cannot use a limit or offset", ErrInvalidQuery)
+		}
+		var joinArgs []any
+		joinStmt, joinArgs, err = buildJoins(query.Join, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		target = identifier(dialect, query.Table) + " "
+		args = append(args, joinArgs...)
+	case dialect.Supports(FeatureDeleteUsing):
+		sources, sourceArgs, joinConditions, err := buildJoinSources(query.Join, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		usingStmt = " USING " + sources
+		args = append(args, sourceArgs...)
+		condition = joinCondition(joinConditions, condition)
+	default:
+		return "", nil, requireFeature(dialect, FeatureMutationJoin)
+	}
+
+	outputStmt, returningStmt, err := buildReturning(query.Returning, "DELETED", dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
 	stmt := fmt.Sprintf(
-		"DELETE FROM %s",
-		query.Table,
+		"%sDELETE %sFROM %s%s%s%s",
+		withStmt,
+		target,
+		identifier(dialect, query.Table),
+		joinStmt,
+		outputStmt,
+		usingStmt,
 	)
 
-	args := make([]any, 0)
-
-	if query.Condition != nil {
-		if conditionsStmt, conditionArgs := query.Condition.Build(); len(conditionsStmt) > 0 {
+	if condition != nil {
+		conditionsStmt, conditionArgs, err := buildPart(condition, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(conditionsStmt) > 0 {
 			stmt += fmt.Sprintf(" WHERE %s", conditionsStmt)
 			args = append(args, conditionArgs...)
 		}
 	}
 
-	if query.Limit > 0 {
-		stmt += fmt.Sprintf(" LIMIT %d", query.Limit)
-	}
+	stmt += limitOffset(query.Limit, query.Offset)
 
-	if query.Offset > 0 {
-		stmt += fmt.Sprintf(" OFFSET %d", query.Offset)
-	}
+	stmt += returningStmt
 
-	return stmt, args
+	return stmt, args, nil
 }
 
 type TypedDeleteQuery[T any] struct {
@@ -46,6 +107,8 @@ type TypedDeleteQuery[T any] struct {
 
 	Condition func(target *T) Condition
 	Target    *T
+	// ReturningReferences returns references to the fields of the target that Returning is scanned into.
+	ReturningReferences func(target *T) []any
 }
 
 func (query TypedDeleteQuery[T]) Prepare() DeleteQuery {
@@ -56,3 +119,11 @@ func (query TypedDeleteQuery[T]) Prepare() DeleteQuery {
 func (query TypedDeleteQuery[T]) Build() (string, []any) {
 	return query.Prepare().Build()
 }
+
+func (query TypedDeleteQuery[T]) BuildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().BuildDialect(dialect)
+}
+
+func (query TypedDeleteQuery[T]) buildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().buildDialect(dialect)
+}
@@ -0,0 +1,389 @@
+package qry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect controls how queries are rendered for a specific database engine.
+type Dialect interface {
+	// Name returns the name of the dialect.
+	Name() string
+	// Placeholder returns the bind parameter placeholder for the arg at the given 1-based position.
+	Placeholder(position int) string
+	// QuoteIdentifier quotes the given identifier, such as a table or column name.
+	// Each part of a dot separated identifier is quoted separately.
+	// Queries only quote identifiers when rendered with a Dialect returned by QuoteIdentifiers.
+	QuoteIdentifier(identifier string) string
+	// Paginate returns the SQL used to limit and offset the rows returned by a select.
+	// The prefix is placed directly after SELECT and the suffix is appended to the end of the statement.
+	// ordered is true when the select already has an ORDER BY clause.
+	Paginate(limit int64, offset int64, ordered bool) (prefix string, suffix string)
+	// Supports returns true if the dialect supports the given Feature.
+	Supports(feature Feature) bool
+	// Savepoint returns the statements used to create, release and roll back to the named savepoint.
+	// release is empty if the dialect has no way to release a savepoint.
+	Savepoint(name string) (create string, release string, rollback string)
+	// MaxParameters returns the maximum number of bind parameters allowed in a single statement.
+	MaxParameters() int
+}
+
+// DialectQuery is a Query that can be rendered for a specific Dialect.
+//
+// The Build method of a DialectQuery renders it for MySQL. As Build cannot return an error, an invalid query, or
+// one using a Feature that MySQL does not support, produces an empty statement and nil args. Use BuildDialect to
+// find out why a query could not be built.
+type DialectQuery interface {
+	Query
+	// BuildDialect returns an SQL statement and the related args, rendered for the given Dialect.
+	BuildDialect(dialect Dialect) (string, []any, error)
+}
+
+// Feature is an optional piece of SQL syntax that is not supported by every Dialect.
+type Feature string
+
+// FeatureMutationLimit is support for LIMIT and OFFSET on INSERT, UPDATE and DELETE statements.
+const FeatureMutationLimit Feature = "mutation_limit"
+
+// FeatureDistinctOn is support for SELECT DISTINCT ON.
+const FeatureDistinctOn Feature = "distinct_on"
+
+// ErrUnsupportedFeature is returned when a query uses a Feature that is not supported by the Dialect.
+var ErrUnsupportedFeature = errors.New("feature not supported by dialect")
+
+var (
+	// MySQL renders queries for MySQL and MariaDB.
+	// This is the Dialect used by Build and by a Repository without a Dialect.
+	MySQL Dialect = mysqlDialect{}
+	// Postgres renders queries for PostgreSQL.
+	Postgres Dialect = postgresDialect{}
+	// SQLite renders queries for SQLite.
+	SQLite Dialect = sqliteDialect{}
+	// SQLServer renders queries for Microsoft SQL Server.
+	SQLServer Dialect = sqlServerDialect{}
+)
+
+type mysqlDialect struct{}
+
+var mysqlFeatures = map[Feature]bool{
+	FeatureMutationLimit:        true,
+	FeatureOnDuplicateKeyUpdate: true,
+	FeatureWithRecursive:        true,
+	FeatureLock:                 true,
+	FeatureDefaultValue:         true,
+	FeatureMutationJoin:         true,
+	FeatureCompoundParentheses:  true,
+}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Placeholder(position int) string {
+	return "?"
+}
+
+func (mysqlDialect) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier, "`", "`")
+}
+
+func (mysqlDialect) Paginate(limit int64, offset int64, ordered bool) (string, string) {
+	return "", limitOffset(limit, offset)
+}
+
+func (mysqlDialect) Supports(feature Feature) bool {
+	return mysqlFeatures[feature]
+}
+
+func (mysqlDialect) Savepoint(name string) (string, string, string) {
+	return standardSavepoint(name)
+}
+
+func (mysqlDialect) MaxParameters() int {
+	return 65535
+}
+
+type postgresDialect struct{}
+
+var postgresFeatures = map[Feature]bool{
+	FeatureOnConflict:          true,
+	FeatureReturning:           true,
+	FeatureWithRecursive:       true,
+	FeatureLock:                true,
+	FeatureLockKey:             true,
+	FeatureDistinctOn:          true,
+	FeatureDefaultValue:        true,
+	FeatureUpdateFromValues:    true,
+	FeatureUpdateFrom:          true,
+	FeatureDeleteUsing:         true,
+	FeatureCompoundParentheses: true,
+}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Placeholder(position int) string {
+	return fmt.Sprintf("$%d", position)
+}
+
+func (postgresDialect) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+func (postgresDialect) Paginate(limit int64, offset int64, ordered bool) (string, string) {
+	return "", limitOffset(limit, offset)
+}
+
+func (postgresDialect) Supports(feature Feature) bool {
+	return postgresFeatures[feature]
+}
+
+func (postgresDialect) Savepoint(name string) (string, string, string) {
+	return standardSavepoint(name)
+}
+
+func (postgresDialect) MaxParameters() int {
+	return 65535
+}
+
+type sqliteDialect struct{}
+
+var sqliteFeatures = map[Feature]bool{
+	FeatureOnConflict:    true,
+	FeatureReturning:     true,
+	FeatureWithRecursive: true,
+	FeatureUpdateFrom:    true,
+}
+
+func (sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (sqliteDialect) Placeholder(position int) string {
+	return "?"
+}
+
+func (sqliteDialect) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier, `"`, `"`)
+}
+
+func (sqliteDialect) Paginate(limit int64, offset int64, ordered bool) (string, string) {
+	// SQLite does not allow an OFFSET without a LIMIT, so use a negative limit to mean no limit.
+	if limit <= 0 && offset > 0 {
+		return "", fmt.Sprintf(" LIMIT -1 OFFSET %d", offset)
+	}
+	return "", limitOffset(limit, offset)
+}
+
+func (sqliteDialect) Supports(feature Feature) bool {
+	return sqliteFeatures[feature]
+}
+
+func (sqliteDialect) Savepoint(name string) (string, string, string) {
+	return standardSavepoint(name)
+}
+
+func (sqliteDialect) MaxParameters() int {
+	// SQLite versions before 3.32.0 are limited to 999.
+	return 32766
+}
+
+type sqlServerDialect struct{}
+
+var sqlServerFeatures = map[Feature]bool{
+	FeatureOutput:              true,
+	FeatureDefaultValue:        true,
+	FeatureCompoundParentheses: true,
+}
+
+func (sqlServerDialect) Name() string {
+	return "sqlserver"
+}
+
+func (sqlServerDialect) Placeholder(position int) string {
+	return fmt.Sprintf("@p%d", position)
+}
+
+func (sqlServerDialect) QuoteIdentifier(identifier string) string {
+	return quoteIdentifier(identifier, "[", "]")
+}
+
+func (sqlServerDialect) Paginate(limit int64, offset int64, ordered bool) (string, string) {
+	if offset <= 0 {
+		if limit > 0 {
+			return fmt.Sprintf("TOP %d ", limit), ""
+		}
+		return "", ""
+	}
+
+	// OFFSET FETCH is only valid after an ORDER BY.
+	suffix := ""
+	if !ordered {
+		suffix += " ORDER BY (SELECT NULL)"
+	}
+	suffix += fmt.Sprintf(" OFFSET %d ROWS", offset)
+	if limit > 0 {
+		suffix += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return "", suffix
+}
+
+func (sqlServerDialect) Supports(feature Feature) bool {
+	return sqlServerFeatures[feature]
+}
+
+func (sqlServerDialect) Savepoint(name string) (string, string, string) {
+	return "SAVE TRANSACTION " + name, "", "ROLLBACK TRANSACTION " + name
+}
+
+func (sqlServerDialect) MaxParameters() int {
+	return 2100
+}
+
+// limitOffset returns a LIMIT and OFFSET clause for the given values, omitting either if unused.
+func limitOffset(limit int64, offset int64) string {
+	stmt := ""
+	if limit > 0 {
+		stmt += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if offset > 0 {
+		stmt += fmt.Sprintf(" OFFSET %d", offset)
+	}
+	return stmt
+}
+
+// standardSavepoint returns the SQL standard savepoint statements for the given name.
+func standardSavepoint(name string) (string, string, string) {
+	return "SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name
+}
+
+// quoteIdentifier wraps each dot separated part of the identifier in the given quotes.
+// Any closing quotes within a part are escaped by doubling them.
+func quoteIdentifier(identifier string, open string, close string) string {
+	parts := strings.Split(identifier, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = open + strings.ReplaceAll(part, close, close+close) + close
+	}
+	return strings.Join(parts, ".")
+}
+
+// QuoteIdentifiers returns a Dialect that renders queries in the same way as the given Dialect, but also quotes
+// table and field names using QuoteIdentifier, such as when they are reserved words.
+// Only plain names such as id, users.id and users.* are quoted. Expressions, aliases and the SQL of a
+// RawCondition, RawQuery or Expr are rendered as they are.
+func QuoteIdentifiers(dialect Dialect) Dialect {
+	if _, ok := dialect.(quotedDialect); ok {
+		return dialect
+	}
+	return quotedDialect{Dialect: dialect}
+}
+
+// quotedDialect is a Dialect returned by QuoteIdentifiers.
+type quotedDialect struct {
+	Dialect
+}
+
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*(\.([A-Za-z_][A-Za-z0-9_$]*|\*))*$`)
+
+// identifier returns the given table or field name, quoted if the dialect was returned by QuoteIdentifiers.
+func identifier[S ~string](dialect Dialect, name S) string {
+	if _, ok := dialect.(quotedDialect); !ok || !plainIdentifier.MatchString(string(name)) {
+		return string(name)
+	}
+	return dialect.QuoteIdentifier(string(name))
+}
+
+// identifiers returns the given table or field names as identifiers, separated by commas.
+func identifiers[S ~string](dialect Dialect, names []S) string {
+	return strings.Join(genericMap(names, func(name S) string {
+		return identifier(dialect, name)
+	}), ", ")
+}
+
+// requireFeature returns an ErrUnsupportedFeature error if the dialect does not support the given feature.
+func requireFeature(dialect Dialect, feature Feature) error {
+	if !dialect.Supports(feature) {
+		return fmt.Errorf("%w: %s does not support %s", ErrUnsupportedFeature, dialect.Name(), feature)
+	}
+	return nil
+}
+
+// dialectBuilder is implemented by queries and conditions whose SQL depends on the Dialect.
+// Placeholders are always rendered as ? and are rebound once the full statement has been built.
+type dialectBuilder interface {
+	buildDialect(dialect Dialect) (string, []any, error)
+}
+
+// buildPart builds the given query or condition for use within a larger statement.
+func buildPart(part Query, dialect Dialect) (string, []any, error) {
+	if builder, ok := part.(dialectBuilder); ok {
+		return builder.buildDialect(dialect)
+	}
+	stmt, args := part.Build()
+	return stmt, args, nil
+}
+
+// buildStatement builds a complete statement and rebinds its placeholders for the dialect.
+func buildStatement(builder dialectBuilder, dialect Dialect) (string, []any, error) {
+	stmt, args, err := builder.buildDialect(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	return rebind(dialect, stmt), args, nil
+}
+
+// rebind replaces each ? placeholder in the statement with the placeholder used by the dialect, and each ?? with
+// a literal ?. Question marks within quoted strings and identifiers are left untouched.
+func rebind(dialect Dialect, stmt string) string {
+	parts := splitPlaceholders(stmt, true)
+
+	var b strings.Builder
+	b.Grow(len(stmt))
+	for i, part := range parts {
+		if i > 0 {
+			b.WriteString(dialect.Placeholder(i))
+		}
+		b.WriteString(part)
+	}
+
+	return b.String()
+}
+
+// splitPlaceholders splits the statement around each ? placeholder.
+// Question marks within quoted strings and identifiers are not placeholders, and nor is ??, which escapes a
+// literal ? such as the Postgres jsonb operator. unescape replaces each ?? with ? in the returned parts.
+func splitPlaceholders(stmt string, unescape bool) []string {
+	parts := make([]string, 0)
+
+	var part strings.Builder
+	var quote byte
+	for i := 0; i < len(stmt); i++ {
+		c := stmt[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?' && i+1 < len(stmt) && stmt[i+1] == '?':
+			i++
+			if !unescape {
+				part.WriteByte(c)
+			}
+		case c == '?':
+			parts = append(parts, part.String())
+			part.Reset()
+			continue
+		}
+		part.WriteByte(c)
+	}
+
+	return append(parts, part.String())
+}
@@ -0,0 +1,396 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestBuildDialect(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	selectQuery := qry.SelectQuery{
+		Fields:    []qry.Field{"id", "name"},
+		Table:     "users",
+		Condition: qry.And(qry.Equal("name", "Tom"), qry.In("status", []string{"a", "b"})),
+	}
+	orderedSelectQuery := selectQuery
+	orderedSelectQuery.OrderBy = []qry.OrderBy{{Field: "id", Direction: qry.Ascending}}
+	limitedSelectQuery := selectQuery
+	limitedSelectQuery.Limit = 5
+	pagedSelectQuery := orderedSelectQuery
+	pagedSelectQuery.Limit = 5
+	pagedSelectQuery.Offset = 10
+	offsetSelectQuery := selectQuery
+	offsetSelectQuery.Offset = 10
+
+	tests := []def{
+		{
+			name:    "MySQL select",
+			query:   pagedSelectQuery,
+			dialect: qry.MySQL,
+			expStmt: "SELECT id, name FROM users WHERE (name = ? AND status IN (?, ?)) ORDER BY id ASC LIMIT 5 OFFSET 10",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name:    "Postgres select",
+			query:   pagedSelectQuery,
+			dialect: qry.Postgres,
+			expStmt: "SELECT id, name FROM users WHERE (name = $1 AND status IN ($2, $3)) ORDER BY id ASC LIMIT 5 OFFSET 10",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name:    "SQLite select with offset only",
+			query:   offsetSelectQuery,
+			dialect: qry.SQLite,
+			expStmt: "SELECT id, name FROM users WHERE (name = ? AND status IN (?, ?)) LIMIT -1 OFFSET 10",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name:    "SQL Server select with limit",
+			query:   limitedSelectQuery,
+			dialect: qry.SQLServer,
+			expStmt: "SELECT TOP 5 id, name FROM users WHERE (name = @p1 AND status IN (@p2, @p3))",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name:    "SQL Server select with limit and offset",
+			query:   pagedSelectQuery,
+			dialect: qry.SQLServer,
+			expStmt: "SELECT id, name FROM users WHERE (name = @p1 AND status IN (@p2, @p3)) ORDER BY id ASC OFFSET 10 ROWS FETCH NEXT 5 ROWS ONLY",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name:    "SQL Server select with offset and no order",
+			query:   offsetSelectQuery,
+			dialect: qry.SQLServer,
+			expStmt: "SELECT id, name FROM users WHERE (name = @p1 AND status IN (@p2, @p3)) ORDER BY (SELECT NULL) OFFSET 10 ROWS",
+			expArgs: []any{"Tom", "a", "b"},
+		},
+		{
+			name: "Postgres ignores quoted placeholders",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "users",
+				Condition: qry.And(&qry.RawCondition{SQL: "name != 'what?'"}, qry.Equal("id", 1)),
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM users WHERE (name != 'what?' AND id = $1)",
+			expArgs: []any{1},
+		},
+		{
+			name: "Postgres escaped question mark",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "users",
+				Condition: &qry.RawCondition{SQL: "data ?? 'k' AND data ??| array['a', 'b'] AND id = ?", Args: []any{1}},
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM users WHERE data ? 'k' AND data ?| array['a', 'b'] AND id = $1",
+			expArgs: []any{1},
+		},
+		{
+			name: "Postgres escaped question mark in expression",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"tagged": qry.Raw("tags ?? ?", "admin"),
+				},
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET tagged = tags ? $1 WHERE id = $2",
+			expArgs: []any{"admin", 1},
+		},
+		{
+			name: "Postgres insert",
+			query: qry.InsertQuery{
+				Table:  "users",
+				Fields: []qry.Field{"id", "name"},
+				Values: [][]any{{1, "Tom"}},
+			},
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO users(id, name) VALUES ($1, $2)",
+			expArgs: []any{1, "Tom"},
+		},
+		{
+			name: "Postgres update",
+			query: qry.UpdateQuery{
+				Table:     "users",
+				Values:    map[qry.Field]any{"name": "Tom"},
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET name = $1 WHERE id = $2",
+			expArgs: []any{"Tom", 1},
+		},
+		{
+			name: "SQL Server delete",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.SQLServer,
+			expStmt: "DELETE FROM users WHERE id = @p1",
+			expArgs: []any{1},
+		},
+		{
+			name: "MySQL delete with limit",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Condition: qry.Equal("id", 1),
+				Limit:     1,
+			},
+			dialect: qry.MySQL,
+			expStmt: "DELETE FROM users WHERE id = ? LIMIT 1",
+			expArgs: []any{1},
+		},
+		{
+			name: "Postgres delete with limit",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Condition: qry.Equal("id", 1),
+				Limit:     1,
+			},
+			dialect: qry.Postgres,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name: "Postgres select distinct on",
+			query: qry.SelectQuery{
+				DistinctOn: []qry.Field{"user_id"},
+				Fields:     []qry.Field{"user_id", "id", "created_at"},
+				Table:      "orders",
+				OrderBy: []qry.OrderBy{
+					{Field: "user_id", Direction: qry.Ascending},
+					{Field: "created_at", Direction: qry.Descending},
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT DISTINCT ON (user_id) user_id, id, created_at FROM orders ORDER BY user_id ASC, created_at DESC",
+			expArgs: []any{},
+		},
+		{
+			name: "MySQL select distinct on",
+			query: qry.SelectQuery{
+				DistinctOn: []qry.Field{"user_id"},
+				Fields:     []qry.Field{"user_id", "id"},
+				Table:      "orders",
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name: "SQL Server select distinct with limit",
+			query: qry.SelectQuery{
+				Distinct: true,
+				Fields:   []qry.Field{"user_id"},
+				Table:    "orders",
+				Limit:    5,
+			},
+			dialect: qry.SQLServer,
+			expStmt: "SELECT DISTINCT TOP 5 user_id FROM orders",
+			expArgs: []any{},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func TestDialect_QuoteIdentifier(t *testing.T) {
+	type def struct {
+		name       string
+		dialect    qry.Dialect
+		identifier string
+		exp        string
+	}
+	tests := []def{
+		{
+			name:       "MySQL",
+			dialect:    qry.MySQL,
+			identifier: "users.id",
+			exp:        "`users`.`id`",
+		},
+		{
+			name:       "Postgres",
+			dialect:    qry.Postgres,
+			identifier: `odd"name`,
+			exp:        `"odd""name"`,
+		},
+		{
+			name:       "SQLite wildcard",
+			dialect:    qry.SQLite,
+			identifier: "users.*",
+			exp:        `"users".*`,
+		},
+		{
+			name:       "SQL Server",
+			dialect:    qry.SQLServer,
+			identifier: "dbo.users",
+			exp:        "[dbo].[users]",
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			checkDiff(t, tc.exp, tc.dialect.QuoteIdentifier(tc.identifier))
+		})
+	}
+}
+
+func TestQuoteIdentifiers(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+	}
+
+	tests := []def{
+		{
+			name: "Postgres select",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id", "user", qry.Count("*").As("total")},
+				Table:  "order",
+				Join: []qry.Join{
+					{Table: "users", On: &qry.RawCondition{SQL: `"users".id = "order".user`}},
+				},
+				Condition: qry.And(qry.Equal("order.status", "paid"), qry.In("group", []int{1, 2}), qry.IsNull("users.deleted_at")),
+				GroupBy:   []qry.Field{"id", "user"},
+				OrderBy:   []qry.OrderBy{{Field: "id", Direction: qry.Descending}},
+			},
+			dialect: qry.QuoteIdentifiers(qry.Postgres),
+			expStmt: `SELECT "id", "user", COUNT(*) AS total FROM "order" JOIN "users" ON "users".id = "order".user WHERE ("order"."status" = $1 AND "group" IN ($2, $3) AND "users"."deleted_at" IS NULL) GROUP BY "id", "user" ORDER BY "id" DESC`,
+			expArgs: []any{"paid", 1, 2},
+		},
+		{
+			name: "MySQL upsert",
+			query: qry.InsertQuery{
+				Table:  "groups",
+				Fields: []qry.Field{"key", "name"},
+				Values: [][]any{{"a", "A"}},
+				OnConflict: &qry.OnConflict{
+					Update: qry.ExcludedValues("name"),
+				},
+			},
+			dialect: qry.QuoteIdentifiers(qry.MySQL),
+			expStmt: "INSERT INTO `groups`(`key`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+			expArgs: []any{"a", "A"},
+		},
+		{
+			name: "SQL Server update output",
+			query: qry.UpdateQuery{
+				Table: "user",
+				Values: map[qry.Field]any{
+					"status": "active",
+				},
+				Condition: qry.Between("id", 1, 10),
+				Returning: []qry.Field{"id"},
+			},
+			dialect: qry.QuoteIdentifiers(qry.SQLServer),
+			expStmt: "UPDATE [user] SET [status] = @p1 OUTPUT INSERTED.[id] WHERE [id] BETWEEN @p2 AND @p3",
+			expArgs: []any{"active", 1, 10},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
+
+func TestBuild_Invalid(t *testing.T) {
+	type def struct {
+		name  string
+		query qry.DialectQuery
+	}
+
+	tests := []def{
+		{
+			name: "Insert returning",
+			query: qry.InsertQuery{
+				Table:     "users",
+				Fields:    []qry.Field{"name"},
+				Values:    [][]any{{"Tom"}},
+				Returning: []qry.Field{"id"},
+			},
+		},
+		{
+			name: "Update many without key",
+			query: qry.UpdateManyQuery{
+				Table:  "users",
+				Fields: []qry.Field{"name"},
+				Values: [][]any{{"Tom"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, _, err := tc.query.BuildDialect(qry.MySQL); err == nil {
+				t.Errorf("expected BuildDialect to return an error")
+			}
+
+			gotStmt, gotArgs := tc.query.Build()
+
+			if !checkDiffMsg(t, "", gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, []any(nil), gotArgs, "invalid args")
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package qry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeatureDefaultValue is support for DEFAULT as a value in INSERT and UPDATE statements.
+const FeatureDefaultValue Feature = "default_value"
+
+// Expr is an SQL expression used as a value in an INSERT or UPDATE, such as balance - ?.
+// It is rendered inline rather than being bound as a single arg.
+//
+// Each ? in SQL is replaced by the arg at the same position. Args that are themselves rendered inline,
+// such as another Expr, replace their ? with their own SQL. Any other arg is bound.
+type Expr struct {
+	SQL  string
+	Args []any
+}
+
+// Raw returns an Expr with the given SQL and args.
+// Each ? outside of a quoted string or identifier is a placeholder for the next arg. Use ?? for a literal ?.
+func Raw(sql string, args ...any) Expr {
+	return Expr{
+		SQL:  sql,
+		Args: args,
+	}
+}
+
+// Increment returns an Expr that adds the given amount to the current value of the field.
+func Increment(field Field, amount any) Expr {
+	return Raw(fmt.Sprintf("%s + ?", field), amount)
+}
+
+// Decrement returns an Expr that subtracts the given amount from the current value of the field.
+func Decrement(field Field, amount any) Expr {
+	return Raw(fmt.Sprintf("%s - ?", field), amount)
+}
+
+// Now returns an Expr for the current date and time of the database.
+func Now() Expr {
+	return Raw("CURRENT_TIMESTAMP")
+}
+
+// Coalesce returns an Expr for the current value of the field, or the fallback if the field is NULL.
+func Coalesce(field Field, fallback any) Expr {
+	return Raw(fmt.Sprintf("COALESCE(%s, ?)", field), fallback)
+}
+
+// Add returns an Expr that adds the given amount to the expression.
+// e.g. Coalesce("counter", 0).Add(1).
+func (expr Expr) Add(amount any) Expr {
+	return Raw("? + ?", expr, amount)
+}
+
+// Subtract returns an Expr that subtracts the given amount from the expression.
+func (expr Expr) Subtract(amount any) Expr {
+	return Raw("? - ?", expr, amount)
+}
+
+func (expr Expr) buildValue(dialect Dialect) (string, []any, error) {
+	parts := splitPlaceholders(expr.SQL, false)
+	if len(parts)-1 != len(expr.Args) {
+		return "", nil, fmt.Errorf("%w: expression %q has %d placeholders but %d args", ErrInvalidQuery, expr.SQL, len(parts)-1, len(expr.Args))
+	}
+
+	stmt := &strings.Builder{}
+	args := make([]any, 0, len(expr.Args))
+	for i, arg := range expr.Args {
+		valueStmt, valueArgs, err := buildValue(arg, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		stmt.WriteString(parts[i])
+		stmt.WriteString(valueStmt)
+		args = append(args, valueArgs...)
+	}
+	stmt.WriteString(parts[len(parts)-1])
+
+	return stmt.String(), args, nil
+}
+
+// DefaultValue is a value that sets a field to its column default.
+type DefaultValue struct{}
+
+// Default returns a value that sets a field to its column default.
+// It requires FeatureDefaultValue.
+func Default() DefaultValue {
+	return DefaultValue{}
+}
+
+func (value DefaultValue) buildValue(dialect Dialect) (string, []any, error) {
+	if err := requireFeature(dialect, FeatureDefaultValue); err != nil {
+		return "", nil, err
+	}
+	return "DEFAULT", []any{}, nil
+}
@@ -0,0 +1,159 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestExpr(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	tests := []def{
+		{
+			name: "Update decrement",
+			query: qry.UpdateQuery{
+				Table: "accounts",
+				Values: map[qry.Field]any{
+					"balance": qry.Decrement("balance", 50),
+				},
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.MySQL,
+			expStmt: "UPDATE accounts SET balance = balance - ? WHERE id = ?",
+			expArgs: []any{50, 1},
+		},
+		{
+			name: "Update mixed values",
+			query: qry.UpdateQuery{
+				Table: "accounts",
+				Values: map[qry.Field]any{
+					"version":    qry.Increment("version", 1),
+					"name":       "Tom",
+					"updated_at": qry.Now(),
+				},
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE accounts SET name = $1, updated_at = CURRENT_TIMESTAMP, version = version + $2 WHERE id = $3",
+			expArgs: []any{"Tom", 1, 1},
+		},
+		{
+			name: "Update coalesce",
+			query: qry.UpdateQuery{
+				Table: "pages",
+				Values: map[qry.Field]any{
+					"counter": qry.Coalesce("counter", 0).Add(1),
+				},
+				Condition: qry.Equal("id", 1),
+			},
+			dialect: qry.SQLServer,
+			expStmt: "UPDATE pages SET counter = COALESCE(counter, @p1) + @p2 WHERE id = @p3",
+			expArgs: []any{0, 1, 1},
+		},
+		{
+			name: "Update raw",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"name": qry.Raw("LOWER(?)", "TOM"),
+				},
+			},
+			dialect: qry.MySQL,
+			expStmt: "UPDATE users SET name = LOWER(?)",
+			expArgs: []any{"TOM"},
+		},
+		{
+			name: "Update raw with quoted question mark",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"name": qry.Raw("CONCAT(name, '?', ?)", "!"),
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET name = CONCAT(name, '?', $1)",
+			expArgs: []any{"!"},
+		},
+		{
+			name: "Update default",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"status": qry.Default(),
+				},
+			},
+			dialect: qry.MySQL,
+			expStmt: "UPDATE users SET status = DEFAULT",
+			expArgs: []any{},
+		},
+		{
+			name: "SQLite update default",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"status": qry.Default(),
+				},
+			},
+			dialect: qry.SQLite,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name: "Raw with mismatched args",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"name": qry.Raw("CONCAT(?, ?)", "Tom"),
+				},
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name: "Insert expressions",
+			query: qry.InsertQuery{
+				Table:  "users",
+				Fields: []qry.Field{"name", "created_at", "status"},
+				Values: [][]any{
+					{"Tom", qry.Now(), qry.Default()},
+					{"Jim", qry.Now(), "active"},
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO users(name, created_at, status) VALUES ($1, CURRENT_TIMESTAMP, DEFAULT), ($2, CURRENT_TIMESTAMP, $3)",
+			expArgs: []any{"Tom", "Jim", "active"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
@@ -1,7 +1,49 @@
 package qry
 
+import "fmt"
+
 type Field string
 
 func (f Field) String() string {
 	return string(f)
 }
+
+// As returns a Field that aliases this field with the given name.
+func (f Field) As(alias string) Field {
+	return Field(fmt.Sprintf("%s AS %s", f, alias))
+}
+
+// Count returns a Field that counts the non-NULL values of the given field.
+// Use "*" to count every row.
+func Count(field Field) Field {
+	return aggregate("COUNT", field)
+}
+
+// CountDistinct returns a Field that counts the distinct non-NULL values of the given field.
+func CountDistinct(field Field) Field {
+	return Field(fmt.Sprintf("COUNT(DISTINCT %s)", field))
+}
+
+// Sum returns a Field that sums the values of the given field.
+func Sum(field Field) Field {
+	return aggregate("SUM", field)
+}
+
+// Avg returns a Field that averages the values of the given field.
+func Avg(field Field) Field {
+	return aggregate("AVG", field)
+}
+
+// Min returns a Field that finds the smallest value of the given field.
+func Min(field Field) Field {
+	return aggregate("MIN", field)
+}
+
+// Max returns a Field that finds the largest value of the given field.
+func Max(field Field) Field {
+	return aggregate("MAX", field)
+}
+
+func aggregate(fn string, field Field) Field {
+	return Field(fmt.Sprintf("%s(%s)", fn, field))
+}
@@ -1,6 +1,7 @@
 package qry
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 )
@@ -12,39 +13,127 @@ func Insert() InsertQuery {
 type InsertQuery struct {
 	Fields []Field
 	Values [][]any
+	// Select is the query whose rows are inserted, in place of Values.
+	// When Fields is set, the select must return the same number of columns.
+	Select *SelectQuery
 	Table  string
 	Limit  int64
 	Offset int64
+
+	// Ignore skips rows that conflict with an existing row.
+	Ignore bool
+	// OnConflict controls what happens when a row conflicts with an existing row.
+	OnConflict *OnConflict
+	// Returning is the fields returned for each inserted row.
+	Returning []Field
 }
 
 func (query InsertQuery) Build() (string, []any) {
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
+
+func (query InsertQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query InsertQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	if query.Limit > 0 || query.Offset > 0 {
+		if err := requireFeature(dialect, FeatureMutationLimit); err != nil {
+			return "", nil, err
+		}
+	}
+
+	conflictStmt, conflictArgs, ignore, err := buildInsertConflict(query, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	insert := "INSERT"
+	if ignore {
+		insert = "INSERT IGNORE"
+	}
+
+	outputStmt, returningStmt, err := buildReturning(query.Returning, "INSERTED", dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
 	stmt := fmt.Sprintf(
-		"INSERT INTO %s",
-		query.Table,
+		"%s INTO %s",
+		insert,
+		identifier(dialect, query.Table),
 	)
 
 	args := make([]any, 0)
 
-	stmt += fmt.Sprintf("(%s) ", genericJoin(query.Fields, ", "))
+	if query.Select != nil {
+		selectStmt, selectArgs, err := query.buildSelect(conflictStmt != "" && dialect.Supports(FeatureOnConflict), dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(query.Fields) > 0 {
+			stmt += fmt.Sprintf("(%s)", identifiers(dialect, query.Fields))
+		}
+		stmt += fmt.Sprintf("%s %s", outputStmt, selectStmt)
+		args = append(args, selectArgs...)
+	} else {
+		stmt += fmt.Sprintf("(%s)%s ", identifiers(dialect, query.Fields), outputStmt)
+
+		rows := make([]string, len(query.Values))
+		for i, rowValues := range query.Values {
+			valueStmts := make([]string, len(rowValues))
+			for j, value := range rowValues {
+				valueStmt, valueArgs, err := buildValue(value, dialect)
+				if err != nil {
+					return "", nil, err
+				}
+				valueStmts[j] = valueStmt
+				args = append(args, valueArgs...)
+			}
+			rows[i] = fmt.Sprintf("(%s)", strings.Join(valueStmts, ", "))
+		}
+		stmt += "VALUES " + strings.Join(rows, ", ")
+	}
+
+	stmt += conflictStmt
+	args = append(args, conflictArgs...)
+
+	stmt += returningStmt
 
-	valuesSeparator := ", "
+	stmt += limitOffset(query.Limit, query.Offset)
 
-	stmt += "VALUES "
-	for _, rowValues := range query.Values {
-		args = append(args, rowValues...)
-		stmt += fmt.Sprintf("(%s) ", strings.TrimRight(strings.Repeat("?"+valuesSeparator, len(rowValues)), valuesSeparator))
+	return stmt, args, nil
+}
+
+// buildSelect returns the Select source of the query.
+// onConflict is true when the statement ends with an ON CONFLICT clause.
+func (query InsertQuery) buildSelect(onConflict bool, dialect Dialect) (string, []any, error) {
+	if len(query.Values) > 0 {
+		return "", nil, fmt.Errorf("%w: insert cannot use both Values and Select", ErrInvalidQuery)
 	}
-	stmt = strings.TrimSpace(stmt)
 
-	if query.Limit > 0 {
-		stmt += fmt.Sprintf(" LIMIT %d", query.Limit)
+	selectQuery := *query.Select
+	if len(query.Fields) > 0 && len(selectQuery.Fields) != len(query.Fields) && !selectsAll(selectQuery.Fields) {
+		return "", nil, fmt.Errorf("%w: insert has %d fields but select returns %d", ErrInvalidQuery, len(query.Fields), len(selectQuery.Fields))
 	}
 
-	if query.Offset > 0 {
-		stmt += fmt.Sprintf(" OFFSET %d", query.Offset)
+	if onConflict && selectQuery.Condition == nil {
+		// SQLite cannot tell whether ON follows a join or starts the conflict clause unless the select has a WHERE.
+		selectQuery.Condition = &RawCondition{SQL: "true", Args: []any{}}
 	}
 
-	return stmt, args
+	return buildPart(selectQuery, dialect)
+}
+
+// selectsAll returns true if any of the given fields is a * that selects an unknown number of columns.
+func selectsAll(fields []Field) bool {
+	for _, field := range fields {
+		if field == "*" || strings.HasSuffix(string(field), ".*") {
+			return true
+		}
+	}
+	return false
 }
 
 type TypedInsertQuery[T any] struct {
@@ -52,43 +141,47 @@ type TypedInsertQuery[T any] struct {
 
 	Values  func(target *T) map[Field]any
 	Targets []*T
+	// ReturningReferences returns references to the fields of the target that Returning is scanned into.
+	ReturningReferences func(target *T) []any
 }
 
-func findFieldIndex(field Field, columns []Field) int {
-	for i, f := range columns {
-		if f == field {
-			return i
+// Prepare returns the InsertQuery for the Targets.
+// The columns are every field returned by Values for any of the Targets, in order of field name.
+// Targets that do not return a value for a column, such as for an omitempty field, insert Default for it.
+// For dialects without FeatureDefaultValue, TypedRepository.Insert and InsertReturning instead insert the
+// targets of each set of columns with a separate statement.
+// A query with a Select is returned as is, as its rows come from the select rather than the Targets.
+func (query TypedInsertQuery[T]) Prepare() InsertQuery {
+	if query.Select != nil {
+		return query.InsertQuery
+	}
+
+	targetValues := make([]map[Field]any, len(query.Targets))
+	columnSet := make(map[Field]bool)
+	for i, target := range query.Targets {
+		targetValues[i] = query.Values(target)
+		for field := range targetValues[i] {
+			columnSet[field] = true
 		}
 	}
-	panic("field not found in columns")
-}
 
-func (query TypedInsertQuery[T]) Prepare() InsertQuery {
 	var columns []Field = nil
-	values := make([][]any, 0)
+	if len(query.Targets) > 0 {
+		// Columns are sorted so that the same targets always produce the same SQL.
+		columns = sortedFields(columnSet)
+	}
 
-	for _, target := range query.Targets {
-		targetValues := query.Values(target)
-
-		// Extract the columns of the first target.
-		// Each target should be returning the same values.
-		if columns == nil {
-			columns = make([]Field, len(targetValues))
-			columnIndex := 0
-			for k := range targetValues {
-				columns[columnIndex] = k
-				columnIndex++
+	values := make([][]any, len(targetValues))
+	for i, valuesByField := range targetValues {
+		rowValues := make([]any, len(columns))
+		for j, field := range columns {
+			value, ok := valuesByField[field]
+			if !ok {
+				value = Default()
 			}
+			rowValues[j] = value
 		}
-
-		// Extract the values
-		rowValues := make([]any, len(targetValues))
-		for field, v := range targetValues {
-			rowValueIndex := findFieldIndex(field, columns)
-			rowValues[rowValueIndex] = v
-		}
-
-		values = append(values, rowValues)
+		values[i] = rowValues
 	}
 
 	query.InsertQuery.Fields = columns
@@ -96,6 +189,60 @@ func (query TypedInsertQuery[T]) Prepare() InsertQuery {
 	return query.InsertQuery
 }
 
+// splitByColumns splits the query into one query for each set of columns inserted by the Targets, in order of
+// the first target of each set. This is required by dialects without FeatureDefaultValue, which cannot insert
+// Default for the columns that a target leaves out. Otherwise the query is returned as is.
+func (query TypedInsertQuery[T]) splitByColumns(dialect Dialect) []TypedInsertQuery[T] {
+	if query.Select != nil || len(query.Targets) < 2 || dialect.Supports(FeatureDefaultValue) {
+		return []TypedInsertQuery[T]{query}
+	}
+
+	queries := make([]TypedInsertQuery[T], 0, 1)
+	indexes := make(map[string]int)
+	for _, target := range query.Targets {
+		columns := genericJoin(sortedFields(query.Values(target)), ", ")
+		i, ok := indexes[columns]
+		if !ok {
+			i = len(queries)
+			indexes[columns] = i
+			split := query
+			split.Targets = nil
+			queries = append(queries, split)
+		}
+		queries[i].Targets = append(queries[i].Targets, target)
+	}
+	return queries
+}
+
+// insertResults is the combined result of the statements used to insert targets with different columns.
+type insertResults []sql.Result
+
+// LastInsertId returns the LastInsertId of the last statement.
+func (results insertResults) LastInsertId() (int64, error) {
+	return results[len(results)-1].LastInsertId()
+}
+
+// RowsAffected returns the total rows affected by every statement.
+func (results insertResults) RowsAffected() (int64, error) {
+	var total int64
+	for _, result := range results {
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += rowsAffected
+	}
+	return total, nil
+}
+
 func (query TypedInsertQuery[T]) Build() (string, []any) {
 	return query.Prepare().Build()
 }
+
+func (query TypedInsertQuery[T]) BuildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().BuildDialect(dialect)
+}
+
+func (query TypedInsertQuery[T]) buildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().buildDialect(dialect)
+}
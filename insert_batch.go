@@ -0,0 +1,113 @@
+package qry
+
+import (
+	"context"
+	"fmt"
+)
+
+// InsertBatchOptions controls how TypedRepository.InsertBatch splits targets between statements.
+type InsertBatchOptions struct {
+	// MaxRows is the maximum number of rows inserted by each statement. Zero means no limit.
+	MaxRows int
+	// MaxParameters overrides the maximum number of bind parameters of the Dialect when set,
+	// such as 999 for SQLite versions before 3.32.0.
+	MaxParameters int
+	// Tx executes every statement within a single transaction, so that either every target is inserted or none are.
+	Tx bool
+}
+
+// InsertBatch inserts the given targets using as many statements as is required to keep each statement within
+// the parameter limit of the Dialect and the options. It returns the total number of rows affected.
+//
+// Without Tx, the targets of statements executed before an error remain inserted.
+func (repo TypedRepository[T]) InsertBatch(ctx context.Context, targets []*T, opts InsertBatchOptions) (int64, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "InsertBatch")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	chunks, err := repo.insertChunks(targets, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	insert := func(repo TypedRepository[T]) (int64, error) {
+		var total int64
+		for _, chunk := range chunks {
+			query := repo.InsertQuery()
+			query.Targets = chunk
+			result, err := repo.Insert(ctx, query)
+			if err != nil {
+				return total, err
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return total, fmt.Errorf("could not read rows affected: %w", err)
+			}
+			total += rowsAffected
+		}
+		return total, nil
+	}
+
+	if !opts.Tx {
+		return insert(repo)
+	}
+
+	var total int64
+	err = repo.WithTx(ctx, nil, func(txRepo TypedRepository[T]) error {
+		var err error
+		total, err = insert(txRepo)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// insertChunks splits the given targets into chunks that each fit within a single insert statement.
+func (repo TypedRepository[T]) insertChunks(targets []*T, opts InsertBatchOptions) ([][]*T, error) {
+	dialect := repo.dialect()
+	maxParameters := opts.MaxParameters
+	if maxParameters <= 0 {
+		maxParameters = dialect.MaxParameters()
+	}
+
+	valuesFn := repo.prepareInsertQuery(repo.InsertQuery()).Values
+	if valuesFn == nil {
+		return nil, fmt.Errorf("%w: insert batch requires StandardInsertValues", ErrInvalidQuery)
+	}
+
+	chunks := make([][]*T, 0)
+	var chunk []*T
+	chunkParameters := 0
+
+	for _, target := range targets {
+		rowParameters := 0
+		for _, value := range valuesFn(target) {
+			_, args, err := buildValue(value, dialect)
+			if err != nil {
+				return nil, err
+			}
+			rowParameters += len(args)
+		}
+		if rowParameters > maxParameters {
+			return nil, fmt.Errorf("%w: a single row requires %d parameters, more than the maximum of %d", ErrInvalidQuery, rowParameters, maxParameters)
+		}
+
+		full := chunkParameters+rowParameters > maxParameters || (opts.MaxRows > 0 && len(chunk) >= opts.MaxRows)
+		if full && len(chunk) > 0 {
+			chunks = append(chunks, chunk)
+			chunk, chunkParameters = nil, 0
+		}
+
+		chunk = append(chunk, target)
+		chunkParameters += rowParameters
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,166 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestTypedRepository_InsertBatch(t *testing.T) {
+	errInsert := errors.New("insert failed")
+
+	targets := []*model{
+		{ID: 1, Name: "Tom"},
+		{ID: 2, Name: "Jim"},
+		{ID: 3, Name: "Bob"},
+	}
+
+	type def struct {
+		name    string
+		targets []*model
+		opts    qry.InsertBatchOptions
+		exp     int64
+		expErr  error
+		mockFn  func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name:    "Single statement",
+			targets: targets,
+			exp:     3,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?), (?, ?), (?, ?)").
+					ExpectExec().
+					WithArgs(int64(1), "Tom", int64(2), "Jim", int64(3), "Bob").
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name:    "Max parameters",
+			targets: targets,
+			opts:    qry.InsertBatchOptions{MaxParameters: 5},
+			exp:     3,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?), (?, ?)").
+					ExpectExec().
+					WithArgs(int64(1), "Tom", int64(2), "Jim").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?)").
+					ExpectExec().
+					WithArgs(int64(3), "Bob").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "Max rows",
+			targets: targets,
+			opts:    qry.InsertBatchOptions{MaxRows: 1},
+			exp:     3,
+			mockFn: func(db sqlmock.Sqlmock) {
+				for _, target := range targets {
+					db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?)").
+						ExpectExec().
+						WithArgs(target.ID, target.Name).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			},
+		},
+		{
+			name:    "Transaction",
+			targets: targets,
+			opts:    qry.InsertBatchOptions{MaxRows: 2, Tx: true},
+			exp:     3,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?), (?, ?)").
+					ExpectExec().
+					WithArgs(int64(1), "Tom", int64(2), "Jim").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?)").
+					ExpectExec().
+					WithArgs(int64(3), "Bob").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				db.ExpectCommit()
+			},
+		},
+		{
+			name:    "Transaction rolled back",
+			targets: targets,
+			opts:    qry.InsertBatchOptions{MaxRows: 2, Tx: true},
+			expErr:  errInsert,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?), (?, ?)").
+					ExpectExec().
+					WithArgs(int64(1), "Tom", int64(2), "Jim").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				db.ExpectPrepare("INSERT INTO users(id, name) VALUES (?, ?)").
+					ExpectExec().
+					WithArgs(int64(3), "Bob").
+					WillReturnError(errInsert)
+				db.ExpectRollback()
+			},
+		},
+		{
+			name:    "Row over the limit",
+			targets: targets,
+			opts:    qry.InsertBatchOptions{MaxParameters: 1},
+			expErr:  qry.ErrInvalidQuery,
+			mockFn:  func(db sqlmock.Sqlmock) {},
+		},
+		{
+			name:    "No targets",
+			targets: []*model{},
+			exp:     0,
+			mockFn:  func(db sqlmock.Sqlmock) {},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := qry.TypedRepository[model]{
+				Repository: qry.Repository{
+					DB:    db,
+					Table: "users",
+				},
+				StandardInsertValues: func(target *model) map[qry.Field]any {
+					return map[qry.Field]any{
+						"id":   target.ID,
+						"name": target.Name,
+					}
+				},
+			}
+
+			got, err := repo.InsertBatch(context.Background(), tc.targets, tc.opts)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestTypedInsertQuery_Build(t *testing.T) {
+	type user struct {
+		ID    int64
+		Name  string
+		Email string
+		Age   int
+	}
+
+	type def struct {
+		name    string
+		query   qry.TypedInsertQuery[user]
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name: "Columns are sorted by field",
+			query: qry.TypedInsertQuery[user]{
+				InsertQuery: qry.InsertQuery{
+					Table: "users",
+				},
+				Values: func(target *user) map[qry.Field]any {
+					return map[qry.Field]any{
+						"name":  target.Name,
+						"id":    target.ID,
+						"email": target.Email,
+						"age":   target.Age,
+					}
+				},
+				Targets: []*user{
+					{ID: 1, Name: "Tom", Email: "tom@example.com", Age: 30},
+				},
+			},
+			expStmt: "INSERT INTO users(age, email, id, name) VALUES (?, ?, ?, ?)",
+			expArgs: []any{30, "tom@example.com", int64(1), "Tom"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := tc.query.Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+
+			// Repeated builds must produce byte-identical SQL.
+			for i := 0; i < 50; i++ {
+				repeatStmt, repeatArgs := tc.query.Build()
+				if !checkDiffMsg(t, gotStmt, repeatStmt, "statement changed between builds") {
+					return
+				}
+				if !checkDiffMsg(t, gotArgs, repeatArgs, "args changed between builds") {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestInsertQuery_BuildDialect_Conflict(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.InsertQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	base := qry.InsertQuery{
+		Table:  "users",
+		Fields: []qry.Field{"email", "name"},
+		Values: [][]any{{"tom@example.com", "Tom"}},
+	}
+	withConflict := func(onConflict *qry.OnConflict) qry.InsertQuery {
+		query := base
+		query.OnConflict = onConflict
+		return query
+	}
+	withIgnore := func() qry.InsertQuery {
+		query := base
+		query.Ignore = true
+		return query
+	}
+
+	tests := []def{
+		{
+			name: "MySQL on duplicate key update",
+			query: withConflict(&qry.OnConflict{
+				Update: map[qry.Field]any{
+					"name":       qry.Excluded("name"),
+					"updated_by": "system",
+				},
+			}),
+			dialect: qry.MySQL,
+			expStmt: "INSERT INTO users(email, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), updated_by = ?",
+			expArgs: []any{"tom@example.com", "Tom", "system"},
+		},
+		{
+			name: "MySQL do nothing",
+			query: withConflict(&qry.OnConflict{
+				Fields: []qry.Field{"email"},
+			}),
+			dialect: qry.MySQL,
+			expStmt: "INSERT INTO users(email, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE email = email",
+			expArgs: []any{"tom@example.com", "Tom"},
+		},
+		{
+			name:    "MySQL insert ignore",
+			query:   withIgnore(),
+			dialect: qry.MySQL,
+			expStmt: "INSERT IGNORE INTO users(email, name) VALUES (?, ?)",
+			expArgs: []any{"tom@example.com", "Tom"},
+		},
+		{
+			name: "Postgres on conflict do update",
+			query: withConflict(&qry.OnConflict{
+				Fields: []qry.Field{"email"},
+				Update: qry.ExcludedValues("name"),
+			}),
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO users(email, name) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = excluded.name",
+			expArgs: []any{"tom@example.com", "Tom"},
+		},
+		{
+			name: "SQLite on conflict do nothing",
+			query: withConflict(&qry.OnConflict{
+				Fields: []qry.Field{"email"},
+			}),
+			dialect: qry.SQLite,
+			expStmt: "INSERT INTO users(email, name) VALUES (?, ?) ON CONFLICT (email) DO NOTHING",
+			expArgs: []any{"tom@example.com", "Tom"},
+		},
+		{
+			name:    "Postgres ignore",
+			query:   withIgnore(),
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO users(email, name) VALUES ($1, $2) ON CONFLICT DO NOTHING",
+			expArgs: []any{"tom@example.com", "Tom"},
+		},
+		{
+			name: "Postgres update without conflict fields",
+			query: withConflict(&qry.OnConflict{
+				Update: qry.ExcludedValues("name"),
+			}),
+			dialect: qry.Postgres,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name: "Ignore and on conflict",
+			query: func() qry.InsertQuery {
+				query := withIgnore()
+				query.OnConflict = &qry.OnConflict{Fields: []qry.Field{"email"}}
+				return query
+			}(),
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name:    "SQL Server ignore",
+			query:   withIgnore(),
+			dialect: qry.SQLServer,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func TestInsertQuery_Select(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.InsertQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	archived := &qry.SelectQuery{
+		Fields:    []qry.Field{"id", "name"},
+		Table:     "users",
+		Condition: qry.LessThan("last_seen", "2020-01-01"),
+	}
+
+	tests := []def{
+		{
+			name: "With fields",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name"},
+				Select: archived,
+			},
+			dialect: qry.MySQL,
+			expStmt: "INSERT INTO archived_users(id, name) SELECT id, name FROM users WHERE last_seen < ?",
+			expArgs: []any{"2020-01-01"},
+		},
+		{
+			name: "Without fields",
+			query: qry.InsertQuery{
+				Table: "archived_users",
+				Select: &qry.SelectQuery{
+					Fields: []qry.Field{"*"},
+					Table:  "users",
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO archived_users SELECT * FROM users",
+			expArgs: []any{},
+		},
+		{
+			name: "MySQL on duplicate key update",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name"},
+				Select: archived,
+				OnConflict: &qry.OnConflict{
+					Update: qry.ExcludedValues("name"),
+				},
+			},
+			dialect: qry.MySQL,
+			expStmt: "INSERT INTO archived_users(id, name) SELECT id, name FROM users WHERE last_seen < ? ON DUPLICATE KEY UPDATE name = VALUES(name)",
+			expArgs: []any{"2020-01-01"},
+		},
+		{
+			name: "Postgres on conflict",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name"},
+				Select: archived,
+				OnConflict: &qry.OnConflict{
+					Fields: []qry.Field{"id"},
+					Update: map[qry.Field]any{
+						"name":        qry.Excluded("name"),
+						"archived_by": "system",
+					},
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO archived_users(id, name) SELECT id, name FROM users WHERE last_seen < $1 ON CONFLICT (id) DO UPDATE SET archived_by = $2, name = excluded.name",
+			expArgs: []any{"2020-01-01", "system"},
+		},
+		{
+			name: "SQLite ignore without condition",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name"},
+				Select: &qry.SelectQuery{
+					Fields: []qry.Field{"id", "name"},
+					Table:  "users",
+				},
+				Ignore: true,
+			},
+			dialect: qry.SQLite,
+			expStmt: "INSERT INTO archived_users(id, name) SELECT id, name FROM users WHERE true ON CONFLICT DO NOTHING",
+			expArgs: []any{},
+		},
+		{
+			name: "SQL Server output",
+			query: qry.InsertQuery{
+				Table:     "archived_users",
+				Fields:    []qry.Field{"id", "name"},
+				Select:    archived,
+				Returning: []qry.Field{"id"},
+			},
+			dialect: qry.SQLServer,
+			expStmt: "INSERT INTO archived_users(id, name) OUTPUT INSERTED.id SELECT id, name FROM users WHERE last_seen < @p1",
+			expArgs: []any{"2020-01-01"},
+		},
+		{
+			name: "Mismatched field count",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name", "email"},
+				Select: archived,
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name: "Values and select",
+			query: qry.InsertQuery{
+				Table:  "archived_users",
+				Fields: []qry.Field{"id", "name"},
+				Values: [][]any{{1, "Tom"}},
+				Select: archived,
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
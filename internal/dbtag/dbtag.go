@@ -0,0 +1,83 @@
+// Package dbtag parses the `db` struct tags used to map structs to database columns.
+package dbtag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Key is the struct tag key.
+const Key = "db"
+
+// Tag is a parsed db struct tag.
+//
+// The first element of the tag is the column name and the rest are options, for example:
+//
+//	ID int64 `db:"id,pk,autoincrement"`
+type Tag struct {
+	// Name is the column name.
+	Name string
+	// PrimaryKey is true if the column is part of the primary key.
+	PrimaryKey bool
+	// OmitEmpty is true if the column is omitted from inserts and updates when it holds a zero value.
+	OmitEmpty bool
+	// ReadOnly is true if the column is never inserted or updated.
+	ReadOnly bool
+	// AutoIncrement is true if the column is generated by the database.
+	// It is omitted from inserts when it holds a zero value, and is never updated.
+	AutoIncrement bool
+}
+
+// Parse parses the given tag value.
+// ok is false if the field is not mapped to a column, either because the tag is empty or "-".
+func Parse(value string) (tag Tag, ok bool, err error) {
+	if value == "" || value == "-" {
+		return Tag{}, false, nil
+	}
+
+	parts := strings.Split(value, ",")
+	tag.Name = strings.TrimSpace(parts[0])
+	if tag.Name == "" {
+		return Tag{}, false, fmt.Errorf("missing column name in tag %q", value)
+	}
+
+	for _, option := range parts[1:] {
+		switch strings.TrimSpace(option) {
+		case "pk":
+			tag.PrimaryKey = true
+		case "omitempty":
+			tag.OmitEmpty = true
+		case "readonly":
+			tag.ReadOnly = true
+		case "autoincrement":
+			tag.AutoIncrement = true
+		case "":
+		default:
+			return Tag{}, false, fmt.Errorf("unknown option %q in tag %q", option, value)
+		}
+	}
+
+	return tag, true, nil
+}
+
+// Insertable returns true if the column should be inserted, given whether it currently holds a zero value.
+func (tag Tag) Insertable(zero bool) bool {
+	if tag.ReadOnly {
+		return false
+	}
+	if zero && (tag.AutoIncrement || tag.OmitEmpty) {
+		return false
+	}
+	return true
+}
+
+// Updatable returns true if the column should be updated, given whether it currently holds a zero value.
+func (tag Tag) Updatable(zero bool) bool {
+	if tag.ReadOnly || tag.PrimaryKey || tag.AutoIncrement {
+		return false
+	}
+	if zero && tag.OmitEmpty {
+		return false
+	}
+	return true
+}
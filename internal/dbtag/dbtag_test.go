@@ -0,0 +1,74 @@
+package dbtag_test
+
+import (
+	"github.com/TomWright/qry/internal/dbtag"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type def struct {
+		name   string
+		value  string
+		exp    dbtag.Tag
+		expOk  bool
+		expErr bool
+	}
+	tests := []def{
+		{
+			name:  "Name only",
+			value: "name",
+			exp:   dbtag.Tag{Name: "name"},
+			expOk: true,
+		},
+		{
+			name:  "All options",
+			value: "id,pk,omitempty,readonly,autoincrement",
+			exp: dbtag.Tag{
+				Name:          "id",
+				PrimaryKey:    true,
+				OmitEmpty:     true,
+				ReadOnly:      true,
+				AutoIncrement: true,
+			},
+			expOk: true,
+		},
+		{
+			name:  "Empty",
+			value: "",
+		},
+		{
+			name:  "Skipped",
+			value: "-",
+		},
+		{
+			name:   "Missing name",
+			value:  ",pk",
+			expErr: true,
+		},
+		{
+			name:   "Unknown option",
+			value:  "id,unique",
+			expErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok, err := dbtag.Parse(tc.value)
+			if tc.expErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+			if ok != tc.expOk {
+				t.Errorf("expected ok %v, got %v", tc.expOk, ok)
+			}
+			if !reflect.DeepEqual(tc.exp, got) {
+				t.Errorf("expected %+v, got %+v", tc.exp, got)
+			}
+		})
+	}
+}
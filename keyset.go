@@ -0,0 +1,348 @@
+package qry
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a keyset pagination cursor cannot be decoded or does not match the ordering
+// it is used with.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Keyset paginates a SelectQuery by filtering on the values of the ordered fields of a row seen on another page,
+// rather than by skipping rows with an offset.
+//
+// The OrderBy fields must identify each row uniquely, for example by ending with the primary key, and must not
+// be NULL. Otherwise rows with the same values may be skipped or repeated across pages.
+type Keyset struct {
+	// OrderBy is the ordering of the results. It replaces any ordering on the query.
+	OrderBy []OrderBy
+	// Cursor is a Next or Prev cursor from a previous Page. An empty Cursor returns the first page.
+	Cursor string
+}
+
+// keysetCursor is the decoded form of a cursor.
+type keysetCursor struct {
+	Prev   bool          `json:"p,omitempty"`
+	Values []cursorValue `json:"v"`
+}
+
+// cursorValue is a single value within a cursor, stored along with its type so that it is decoded as
+// the same type.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// Page is a page of results returned by TypedRepository.QueryPage.
+type Page[T any] struct {
+	Items []*T
+	// Next is the cursor for the page after this one, or empty if there are no more results.
+	Next string
+	// Prev is the cursor for the page before this one, or empty if this is the first page.
+	Prev string
+}
+
+// Backward returns true if the cursor was the Prev cursor of a Page.
+// Apply reverses the ordering for such cursors, so the results must be reversed once read.
+func (keyset Keyset) Backward() bool {
+	cursor, err := decodeCursor(keyset.Cursor)
+	return err == nil && cursor.Prev
+}
+
+// Apply returns a copy of the given query that selects the page of results that the cursor points to.
+// The query Limit is used as the page size. The Offset must not be set.
+func (keyset Keyset) Apply(query SelectQuery) (SelectQuery, error) {
+	if len(keyset.OrderBy) == 0 {
+		return query, fmt.Errorf("%w: keyset pagination requires an order", ErrInvalidQuery)
+	}
+	if query.Limit <= 0 {
+		return query, fmt.Errorf("%w: keyset pagination requires a limit", ErrInvalidQuery)
+	}
+	if query.Offset != 0 {
+		return query, fmt.Errorf("%w: keyset pagination cannot be used with an offset", ErrInvalidQuery)
+	}
+
+	cursor, err := decodeCursor(keyset.Cursor)
+	if err != nil {
+		return query, err
+	}
+
+	query.OrderBy = keyset.OrderBy
+	if cursor.Prev {
+		query.OrderBy = reverseOrder(keyset.OrderBy)
+	}
+
+	if len(cursor.Values) == 0 {
+		return query, nil
+	}
+	if len(cursor.Values) != len(keyset.OrderBy) {
+		return query, fmt.Errorf("%w: expected %d values, got %d", ErrInvalidCursor, len(keyset.OrderBy), len(cursor.Values))
+	}
+
+	values := make([]any, len(cursor.Values))
+	for i, value := range cursor.Values {
+		if values[i], err = value.decode(); err != nil {
+			return query, err
+		}
+	}
+
+	condition := keysetCondition(query.OrderBy, values)
+	if query.Condition != nil {
+		condition = And(query.Condition, condition)
+	}
+	query.Condition = condition
+
+	return query, nil
+}
+
+// NextCursor returns a cursor for the page after the row with the given values for each of the OrderBy fields.
+func (keyset Keyset) NextCursor(values []any) (string, error) {
+	return encodeCursor(false, values)
+}
+
+// PrevCursor returns a cursor for the page before the row with the given values for each of the OrderBy fields.
+func (keyset Keyset) PrevCursor(values []any) (string, error) {
+	return encodeCursor(true, values)
+}
+
+// keysetCondition returns a Condition matching rows that come after the given values in the given order.
+// e.g. for a ORDER BY a ASC, b DESC it returns (a > ? OR (a = ? AND b < ?)).
+func keysetCondition(orderBy []OrderBy, values []any) Condition {
+	alternatives := make([]Condition, 0, len(orderBy))
+	for i, order := range orderBy {
+		parts := make([]Condition, 0, i+1)
+		for j, previous := range orderBy[:i] {
+			parts = append(parts, Equal(previous.Field, values[j]))
+		}
+		if order.Direction == Descending {
+			parts = append(parts, LessThan(order.Field, values[i]))
+		} else {
+			parts = append(parts, GreaterThan(order.Field, values[i]))
+		}
+
+		if len(parts) == 1 {
+			alternatives = append(alternatives, parts[0])
+		} else {
+			alternatives = append(alternatives, And(parts...))
+		}
+	}
+	return Or(alternatives...)
+}
+
+// reverseOrder returns the given order with every direction flipped.
+func reverseOrder(orderBy []OrderBy) []OrderBy {
+	return genericMap(orderBy, func(order OrderBy) OrderBy {
+		if order.Direction == Descending {
+			order.Direction = Ascending
+		} else {
+			order.Direction = Descending
+		}
+		return order
+	})
+}
+
+func encodeCursor(prev bool, values []any) (string, error) {
+	cursor := keysetCursor{
+		Prev:   prev,
+		Values: make([]cursorValue, len(values)),
+	}
+	for i, value := range values {
+		encoded, err := encodeCursorValue(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values[i] = encoded
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("could not encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(token string) (keysetCursor, error) {
+	var cursor keysetCursor
+	if token == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return cursor, nil
+}
+
+func encodeCursorValue(value any) (cursorValue, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		driverValue, err := valuer.Value()
+		if err != nil {
+			return cursorValue{}, fmt.Errorf("could not encode cursor value: %w", err)
+		}
+		value = driverValue
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return cursorValue{}, errors.New("could not encode cursor value: keyset fields must not be NULL")
+	case time.Time:
+		return cursorValue{Type: "time", Value: v.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return cursorValue{Type: "bytes", Value: base64.StdEncoding.EncodeToString(v)}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorValue{Type: "int", Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorValue{Type: "uint", Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorValue{Type: "float", Value: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+	case reflect.String:
+		return cursorValue{Type: "string", Value: rv.String()}, nil
+	case reflect.Bool:
+		return cursorValue{Type: "bool", Value: strconv.FormatBool(rv.Bool())}, nil
+	default:
+		return cursorValue{}, fmt.Errorf("could not encode cursor value: unsupported type %T", value)
+	}
+}
+
+func (value cursorValue) decode() (any, error) {
+	var (
+		decoded any
+		err     error
+	)
+	switch value.Type {
+	case "time":
+		decoded, err = time.Parse(time.RFC3339Nano, value.Value)
+	case "bytes":
+		decoded, err = base64.StdEncoding.DecodeString(value.Value)
+	case "int":
+		decoded, err = strconv.ParseInt(value.Value, 10, 64)
+	case "uint":
+		decoded, err = strconv.ParseUint(value.Value, 10, 64)
+	case "float":
+		decoded, err = strconv.ParseFloat(value.Value, 64)
+	case "string":
+		decoded = value.Value
+	case "bool":
+		decoded, err = strconv.ParseBool(value.Value)
+	default:
+		return nil, fmt.Errorf("%w: unknown value type %q", ErrInvalidCursor, value.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return decoded, nil
+}
+
+func (repo TypedRepository[T]) QueryPageFn(ctx context.Context, keyset Keyset, queryFn func(*TypedSelectQuery[T])) (Page[T], error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "QueryPageFn")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query := repo.SelectQuery()
+	queryFn(&query)
+	return repo.QueryPage(ctx, keyset, query)
+}
+
+// QueryPage returns the page of results that the keyset cursor points to, using the query Limit as the page size.
+// The OrderBy fields must be selected by the query so that the cursors of the returned Page can be read from
+// the results.
+func (repo TypedRepository[T]) QueryPage(ctx context.Context, keyset Keyset, query TypedSelectQuery[T]) (Page[T], error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "QueryPage")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareSelectQuery(query)
+
+	fieldIndexes := make([]int, len(keyset.OrderBy))
+	for i, order := range keyset.OrderBy {
+		fieldIndexes[i] = -1
+		for j, field := range query.Fields {
+			if field == order.Field {
+				fieldIndexes[i] = j
+				break
+			}
+		}
+		if fieldIndexes[i] < 0 {
+			return Page[T]{}, fmt.Errorf("%w: keyset field %s must be selected", ErrInvalidQuery, order.Field)
+		}
+	}
+
+	pageSize := query.Limit
+
+	selectQuery, err := keyset.Apply(query.SelectQuery)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	query.SelectQuery = selectQuery
+	// Fetch an extra row to find out if there is another page.
+	query.Limit++
+
+	items, err := repo.Query(ctx, query)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	more := int64(len(items)) > pageSize
+	if more {
+		items = items[:pageSize]
+	}
+
+	backward := keyset.Backward()
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := Page[T]{
+		Items: items,
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	cursorValues := func(target *T) []any {
+		references := query.FieldReferences(target)
+		return genericMap(fieldIndexes, func(index int) any {
+			return reflect.ValueOf(references[index]).Elem().Interface()
+		})
+	}
+
+	hasNext, hasPrev := more, keyset.Cursor != ""
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		if page.Next, err = keyset.NextCursor(cursorValues(items[len(items)-1])); err != nil {
+			return Page[T]{}, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = keyset.PrevCursor(cursorValues(items[0])); err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	return page, nil
+}
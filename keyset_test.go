@@ -0,0 +1,206 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+	"time"
+)
+
+func TestKeyset_Apply(t *testing.T) {
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	orderBy := []qry.OrderBy{
+		{Field: "created_at", Direction: qry.Descending},
+		{Field: "id", Direction: qry.Ascending},
+	}
+
+	mustCursor := func(cursor string, err error) string {
+		if err != nil {
+			t.Fatalf("could not encode cursor: %v", err)
+		}
+		return cursor
+	}
+	next := mustCursor(qry.Keyset{}.NextCursor([]any{createdAt, int64(5)}))
+	prev := mustCursor(qry.Keyset{}.PrevCursor([]any{createdAt, int64(5)}))
+
+	type def struct {
+		name    string
+		keyset  qry.Keyset
+		query   qry.SelectQuery
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+	tests := []def{
+		{
+			name:   "First page",
+			keyset: qry.Keyset{OrderBy: orderBy},
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id", "created_at"},
+				Table:  "users",
+				Limit:  10,
+			},
+			expStmt: "SELECT id, created_at FROM users ORDER BY created_at DESC, id ASC LIMIT 10",
+			expArgs: []any{},
+		},
+		{
+			name:   "Next page",
+			keyset: qry.Keyset{OrderBy: orderBy, Cursor: next},
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id", "created_at"},
+				Table:  "users",
+				Limit:  10,
+			},
+			expStmt: "SELECT id, created_at FROM users WHERE (created_at < ? OR (created_at = ? AND id > ?)) ORDER BY created_at DESC, id ASC LIMIT 10",
+			expArgs: []any{createdAt, createdAt, int64(5)},
+		},
+		{
+			name:   "Previous page",
+			keyset: qry.Keyset{OrderBy: orderBy, Cursor: prev},
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id", "created_at"},
+				Table:  "users",
+				Limit:  10,
+			},
+			expStmt: "SELECT id, created_at FROM users WHERE (created_at > ? OR (created_at = ? AND id < ?)) ORDER BY created_at ASC, id DESC LIMIT 10",
+			expArgs: []any{createdAt, createdAt, int64(5)},
+		},
+		{
+			name:   "Existing condition",
+			keyset: qry.Keyset{OrderBy: orderBy, Cursor: next},
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id", "created_at"},
+				Table:     "users",
+				Condition: qry.Equal("active", true),
+				Limit:     10,
+			},
+			expStmt: "SELECT id, created_at FROM users WHERE (active = ? AND (created_at < ? OR (created_at = ? AND id > ?))) ORDER BY created_at DESC, id ASC LIMIT 10",
+			expArgs: []any{true, createdAt, createdAt, int64(5)},
+		},
+		{
+			name:   "Invalid cursor",
+			keyset: qry.Keyset{OrderBy: orderBy, Cursor: "not a cursor"},
+			query: qry.SelectQuery{
+				Table: "users",
+				Limit: 10,
+			},
+			expErr: qry.ErrInvalidCursor,
+		},
+		{
+			name:   "Cursor for a different order",
+			keyset: qry.Keyset{OrderBy: orderBy[:1], Cursor: next},
+			query: qry.SelectQuery{
+				Table: "users",
+				Limit: 10,
+			},
+			expErr: qry.ErrInvalidCursor,
+		},
+		{
+			name:   "Missing limit",
+			keyset: qry.Keyset{OrderBy: orderBy},
+			query: qry.SelectQuery{
+				Table: "users",
+			},
+			expErr: qry.ErrInvalidQuery,
+		},
+		{
+			name:   "Missing order",
+			keyset: qry.Keyset{},
+			query: qry.SelectQuery{
+				Table: "users",
+				Limit: 10,
+			},
+			expErr: qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := tc.keyset.Apply(tc.query)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			gotStmt, gotArgs := query.Build()
+			checkDiffMsg(t, tc.expStmt, gotStmt, "stmt")
+			checkDiffMsg(t, tc.expArgs, gotArgs, "args")
+		})
+	}
+}
+
+func TestTypedRepository_QueryPage(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	repo := newModelRepo(db)
+	keyset := qry.Keyset{
+		OrderBy: []qry.OrderBy{
+			{Field: "id", Direction: qry.Ascending},
+		},
+	}
+	queryFn := func(query *qry.TypedSelectQuery[model]) {
+		query.Limit = 2
+	}
+	columns := []string{"id", "name"}
+
+	mock.ExpectQuery("SELECT id, name FROM users ORDER BY id ASC LIMIT 3").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(1), "Tom").AddRow(int64(2), "Jim").AddRow(int64(3), "Bob"))
+
+	first, err := repo.QueryPageFn(context.Background(), keyset, queryFn)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiff(t, []*model{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim"}}, first.Items)
+	if first.Next == "" || first.Prev != "" {
+		t.Errorf("expected only a next cursor on the first page, got next %q and prev %q", first.Next, first.Prev)
+	}
+
+	mock.ExpectQuery("SELECT id, name FROM users WHERE (id > ?) ORDER BY id ASC LIMIT 3").
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(3), "Bob"))
+
+	keyset.Cursor = first.Next
+	last, err := repo.QueryPageFn(context.Background(), keyset, queryFn)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiff(t, []*model{{ID: 3, Name: "Bob"}}, last.Items)
+	if last.Next != "" || last.Prev == "" {
+		t.Errorf("expected only a prev cursor on the last page, got next %q and prev %q", last.Next, last.Prev)
+	}
+
+	mock.ExpectQuery("SELECT id, name FROM users WHERE (id < ?) ORDER BY id DESC LIMIT 3").
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(2), "Jim").AddRow(int64(1), "Tom"))
+
+	keyset.Cursor = last.Prev
+	previous, err := repo.QueryPageFn(context.Background(), keyset, queryFn)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiff(t, []*model{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim"}}, previous.Items)
+	if previous.Next == "" || previous.Prev != "" {
+		t.Errorf("expected only a next cursor on the first page, got next %q and prev %q", previous.Next, previous.Prev)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
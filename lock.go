@@ -0,0 +1,93 @@
+package qry
+
+import "fmt"
+
+const (
+	// FeatureLock is support for locking selected rows with FOR UPDATE and FOR SHARE.
+	FeatureLock Feature = "lock"
+	// FeatureLockKey is support for the FOR NO KEY UPDATE and FOR KEY SHARE lock strengths.
+	FeatureLockKey Feature = "lock_key"
+)
+
+// LockStrength is the kind of lock taken on selected rows.
+type LockStrength string
+
+const (
+	// LockForUpdate locks rows against being updated, deleted or locked by other transactions.
+	LockForUpdate LockStrength = "UPDATE"
+	// LockForNoKeyUpdate is a weaker LockForUpdate that does not block LockForKeyShare.
+	LockForNoKeyUpdate LockStrength = "NO KEY UPDATE"
+	// LockForShare locks rows against being updated or deleted by other transactions, while still allowing
+	// them to be read and share locked.
+	LockForShare LockStrength = "SHARE"
+	// LockForKeyShare is a weaker LockForShare that only blocks changes to the key of a row.
+	LockForKeyShare LockStrength = "KEY SHARE"
+)
+
+// LockWait controls what happens when a row to be locked is already locked by another transaction.
+type LockWait string
+
+const (
+	// LockWaitDefault waits for the other transaction to release its lock.
+	LockWaitDefault LockWait = ""
+	// LockNoWait fails the query instead of waiting.
+	LockNoWait LockWait = "NOWAIT"
+	// LockSkipLocked skips any rows that cannot be locked immediately.
+	LockSkipLocked LockWait = "SKIP LOCKED"
+)
+
+// Lock is a locking clause on a SelectQuery, such as FOR UPDATE SKIP LOCKED.
+// It must be used within a transaction for the lock to be held beyond the query.
+type Lock struct {
+	Strength LockStrength
+	// Of restricts the lock to rows from the given tables or aliases.
+	Of   []string
+	Wait LockWait
+}
+
+// ForUpdate returns a Lock that locks selected rows for update.
+func ForUpdate(of ...string) *Lock {
+	return &Lock{
+		Strength: LockForUpdate,
+		Of:       of,
+	}
+}
+
+// ForShare returns a Lock that share locks selected rows.
+func ForShare(of ...string) *Lock {
+	return &Lock{
+		Strength: LockForShare,
+		Of:       of,
+	}
+}
+
+func (lock *Lock) buildDialect(dialect Dialect) (string, error) {
+	if err := requireFeature(dialect, FeatureLock); err != nil {
+		return "", err
+	}
+
+	switch lock.Strength {
+	case LockForUpdate, LockForShare:
+	case LockForNoKeyUpdate, LockForKeyShare:
+		if err := requireFeature(dialect, FeatureLockKey); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%w: unknown lock strength %q", ErrInvalidQuery, lock.Strength)
+	}
+
+	stmt := fmt.Sprintf(" FOR %s", lock.Strength)
+	if len(lock.Of) > 0 {
+		stmt += fmt.Sprintf(" OF %s", identifiers(dialect, lock.Of))
+	}
+
+	switch lock.Wait {
+	case LockWaitDefault:
+	case LockNoWait, LockSkipLocked:
+		stmt += fmt.Sprintf(" %s", lock.Wait)
+	default:
+		return "", fmt.Errorf("%w: unknown lock wait %q", ErrInvalidQuery, lock.Wait)
+	}
+
+	return stmt, nil
+}
@@ -0,0 +1,102 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestSelectQuery_Lock(t *testing.T) {
+	type def struct {
+		name    string
+		lock    *qry.Lock
+		dialect qry.Dialect
+		expStmt string
+		expErr  error
+	}
+
+	tests := []def{
+		{
+			name:    "MySQL for update",
+			lock:    qry.ForUpdate(),
+			dialect: qry.MySQL,
+			expStmt: "SELECT id FROM jobs WHERE status = ? ORDER BY id ASC LIMIT 10 FOR UPDATE",
+		},
+		{
+			name:    "MySQL for share nowait",
+			lock:    &qry.Lock{Strength: qry.LockForShare, Wait: qry.LockNoWait},
+			dialect: qry.MySQL,
+			expStmt: "SELECT id FROM jobs WHERE status = ? ORDER BY id ASC LIMIT 10 FOR SHARE NOWAIT",
+		},
+		{
+			name:    "Postgres for update of skip locked",
+			lock:    &qry.Lock{Strength: qry.LockForUpdate, Of: []string{"jobs"}, Wait: qry.LockSkipLocked},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM jobs WHERE status = $1 ORDER BY id ASC LIMIT 10 FOR UPDATE OF jobs SKIP LOCKED",
+		},
+		{
+			name:    "Postgres for no key update",
+			lock:    &qry.Lock{Strength: qry.LockForNoKeyUpdate},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM jobs WHERE status = $1 ORDER BY id ASC LIMIT 10 FOR NO KEY UPDATE",
+		},
+		{
+			name:    "MySQL for key share",
+			lock:    &qry.Lock{Strength: qry.LockForKeyShare},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name:    "SQLite",
+			lock:    qry.ForUpdate(),
+			dialect: qry.SQLite,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name:    "SQL Server",
+			lock:    qry.ForShare(),
+			dialect: qry.SQLServer,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name:    "Unknown wait",
+			lock:    &qry.Lock{Strength: qry.LockForUpdate, Wait: "WAIT 5"},
+			dialect: qry.Postgres,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query := qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "jobs",
+				Condition: qry.Equal("status", "pending"),
+				OrderBy:   []qry.OrderBy{{Field: "id", Direction: qry.Ascending}},
+				Limit:     10,
+				Lock:      tc.lock,
+			}
+
+			gotStmt, gotArgs, err := query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, []any{"pending"}, gotArgs, "invalid args")
+		})
+	}
+}
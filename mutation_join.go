@@ -0,0 +1,74 @@
+package qry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// FeatureMutationJoin is support for joins within UPDATE and DELETE statements, such as
+	// UPDATE a JOIN b ON ... SET and DELETE a FROM a JOIN b ON ....
+	FeatureMutationJoin Feature = "mutation_join"
+	// FeatureUpdateFrom is support for joining tables to an UPDATE with UPDATE a SET ... FROM b.
+	FeatureUpdateFrom Feature = "update_from"
+	// FeatureDeleteUsing is support for joining tables to a DELETE with DELETE FROM a USING b.
+	FeatureDeleteUsing Feature = "delete_using"
+)
+
+// buildJoins returns each of the given joins, each prefixed with a space.
+func buildJoins(joins []Join, dialect Dialect) (string, []any, error) {
+	stmt := ""
+	args := make([]any, 0)
+	for _, join := range joins {
+		joinStmt, joinArgs, err := join.buildDialect(dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		stmt += fmt.Sprintf(" %s", joinStmt)
+		args = append(args, joinArgs...)
+	}
+	return stmt, args, nil
+}
+
+// buildJoinSources returns the tables of the given joins as a comma separated list, along with their On
+// conditions. It is used by dialects that join tables to an UPDATE or DELETE with FROM or USING, in which case
+// the conditions belong in the WHERE clause. Only inner joins can be expressed this way.
+func buildJoinSources(joins []Join, dialect Dialect) (string, []any, []Condition, error) {
+	sources := make([]string, len(joins))
+	args := make([]any, 0)
+	conditions := make([]Condition, 0, len(joins))
+
+	for i, join := range joins {
+		if join.Type != "" && !strings.EqualFold(join.Type, "INNER") {
+			return "", nil, nil, fmt.Errorf("%w: %s cannot use a %s join in an update or delete", ErrInvalidQuery, dialect.Name(), join.Type)
+		}
+
+		source, sourceArgs, err := buildSource(join.Table, join.From, dialect)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		sources[i] = source
+		args = append(args, sourceArgs...)
+
+		if join.On != nil {
+			conditions = append(conditions, join.On)
+		}
+	}
+
+	return strings.Join(sources, ", "), args, conditions, nil
+}
+
+// joinCondition returns the On conditions of the joins combined with the given condition, which may be nil.
+func joinCondition(joinConditions []Condition, condition Condition) Condition {
+	if condition != nil {
+		joinConditions = append(joinConditions, condition)
+	}
+	switch len(joinConditions) {
+	case 0:
+		return nil
+	case 1:
+		return joinConditions[0]
+	default:
+		return And(joinConditions...)
+	}
+}
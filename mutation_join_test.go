@@ -0,0 +1,193 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestMutationJoin(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	ordersJoin := []qry.Join{
+		{
+			Table: "orders",
+			On:    &qry.RawCondition{SQL: "orders.user_id = users.id"},
+		},
+	}
+	update := qry.UpdateQuery{
+		Table: "users",
+		Join:  ordersJoin,
+		Values: map[qry.Field]any{
+			"status": "active",
+		},
+		Condition: qry.Equal("orders.status", "paid"),
+	}
+	deleteQuery := qry.DeleteQuery{
+		Table:     "users",
+		Join:      ordersJoin,
+		Condition: qry.Equal("orders.status", "refunded"),
+	}
+
+	tests := []def{
+		{
+			name:    "MySQL update join",
+			query:   update,
+			dialect: qry.MySQL,
+			expStmt: "UPDATE users JOIN orders ON orders.user_id = users.id SET status = ? WHERE orders.status = ?",
+			expArgs: []any{"active", "paid"},
+		},
+		{
+			name:    "Postgres update from",
+			query:   update,
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET status = $1 FROM orders WHERE (orders.user_id = users.id AND orders.status = $2)",
+			expArgs: []any{"active", "paid"},
+		},
+		{
+			name:    "SQLite update from",
+			query:   update,
+			dialect: qry.SQLite,
+			expStmt: "UPDATE users SET status = ? FROM orders WHERE (orders.user_id = users.id AND orders.status = ?)",
+			expArgs: []any{"active", "paid"},
+		},
+		{
+			name: "Postgres update from many tables",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Join: []qry.Join{
+					{
+						Table: "orders",
+						On:    &qry.RawCondition{SQL: "orders.user_id = users.id"},
+						Type:  "INNER",
+					},
+					{
+						From: qry.As(qry.SelectQuery{
+							Fields:    []qry.Field{"user_id"},
+							Table:     "refunds",
+							Condition: qry.GreaterThan("amount", 100),
+						}, "large_refunds"),
+						On: &qry.RawCondition{SQL: "large_refunds.user_id = users.id"},
+					},
+				},
+				Values: map[qry.Field]any{
+					"status": "review",
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET status = $1 FROM orders, (SELECT user_id FROM refunds WHERE amount > $2) AS large_refunds WHERE (orders.user_id = users.id AND large_refunds.user_id = users.id)",
+			expArgs: []any{"review", 100},
+		},
+		{
+			name: "Postgres update left join",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Join: []qry.Join{
+					{
+						Table: "orders",
+						On:    &qry.RawCondition{SQL: "orders.user_id = users.id"},
+						Type:  "LEFT",
+					},
+				},
+				Values: map[qry.Field]any{
+					"status": "active",
+				},
+			},
+			dialect: qry.Postgres,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name: "MySQL update join with limit",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Join:  ordersJoin,
+				Values: map[qry.Field]any{
+					"status": "active",
+				},
+				Limit: 1,
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name:    "SQL Server update join",
+			query:   update,
+			dialect: qry.SQLServer,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+		{
+			name:    "MySQL delete join",
+			query:   deleteQuery,
+			dialect: qry.MySQL,
+			expStmt: "DELETE users FROM users JOIN orders ON orders.user_id = users.id WHERE orders.status = ?",
+			expArgs: []any{"refunded"},
+		},
+		{
+			name: "MySQL delete left join",
+			query: qry.DeleteQuery{
+				Table: "users",
+				Join: []qry.Join{
+					{
+						Table: "orders",
+						On:    &qry.RawCondition{SQL: "orders.user_id = users.id"},
+						Type:  "LEFT",
+					},
+				},
+				Condition: qry.IsNull("orders.id"),
+			},
+			dialect: qry.MySQL,
+			expStmt: "DELETE users FROM users LEFT JOIN orders ON orders.user_id = users.id WHERE orders.id IS NULL",
+			expArgs: []any{},
+		},
+		{
+			name: "Postgres delete using with returning",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Join:      ordersJoin,
+				Condition: qry.Equal("orders.status", "refunded"),
+				Returning: []qry.Field{"users.id"},
+			},
+			dialect: qry.Postgres,
+			expStmt: "DELETE FROM users USING orders WHERE (orders.user_id = users.id AND orders.status = $1) RETURNING users.id",
+			expArgs: []any{"refunded"},
+		},
+		{
+			name:    "SQLite delete join",
+			query:   deleteQuery,
+			dialect: qry.SQLite,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package qry
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type Direction string
 
@@ -19,3 +22,10 @@ type OrderBy struct {
 func (ob OrderBy) String() string {
 	return fmt.Sprintf("%s %s", ob.Field.String(), ob.Direction.String())
 }
+
+// buildOrderBy returns the given orderings separated by commas.
+func buildOrderBy(orderBy []OrderBy, dialect Dialect) string {
+	return strings.Join(genericMap(orderBy, func(ob OrderBy) string {
+		return fmt.Sprintf("%s %s", identifier(dialect, ob.Field), ob.Direction)
+	}), ", ")
+}
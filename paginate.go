@@ -0,0 +1,85 @@
+package qry
+
+import (
+	"context"
+	"fmt"
+)
+
+// Pagination is a page of results returned by TypedRepository.Paginate.
+type Pagination[T any] struct {
+	Items []*T
+	// Page is the page number, starting from 1.
+	Page    int64
+	PerPage int64
+	// Total is the number of results across every page.
+	Total      int64
+	TotalPages int64
+}
+
+// HasNext returns true if there is a page after this one.
+func (pagination Pagination[T]) HasNext() bool {
+	return pagination.Page < pagination.TotalPages
+}
+
+// HasPrev returns true if there is a page before this one.
+func (pagination Pagination[T]) HasPrev() bool {
+	return pagination.Page > 1
+}
+
+func (repo TypedRepository[T]) PaginateFn(ctx context.Context, page int64, perPage int64, queryFn func(*TypedSelectQuery[T])) (Pagination[T], error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "PaginateFn")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query := repo.SelectQuery()
+	queryFn(&query)
+	return repo.Paginate(ctx, query, page, perPage)
+}
+
+// Paginate returns the given page of results, numbered from 1, along with the total number of results.
+// The total is counted using the query with its fields, order, limit and offset removed. Grouped queries
+// are counted by group.
+// The query should be ordered so that results are returned in a consistent order across pages.
+func (repo TypedRepository[T]) Paginate(ctx context.Context, query TypedSelectQuery[T], page int64, perPage int64) (Pagination[T], error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Paginate")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	if page < 1 {
+		return Pagination[T]{}, fmt.Errorf("%w: page must be at least 1", ErrInvalidQuery)
+	}
+	if perPage < 1 {
+		return Pagination[T]{}, fmt.Errorf("%w: per page must be at least 1", ErrInvalidQuery)
+	}
+
+	total, err := repo.count(ctx, query)
+	if err != nil {
+		return Pagination[T]{}, err
+	}
+
+	pagination := Pagination[T]{
+		Items:      make([]*T, 0),
+		Page:       page,
+		PerPage:    perPage,
+		Total:      total,
+		TotalPages: (total + perPage - 1) / perPage,
+	}
+
+	query.Limit = perPage
+	query.Offset = (page - 1) * perPage
+
+	if query.Offset >= total {
+		// There are no results on this page so there is no need to query for them.
+		return pagination, nil
+	}
+
+	if pagination.Items, err = repo.Query(ctx, query); err != nil {
+		return Pagination[T]{}, err
+	}
+
+	return pagination, nil
+}
@@ -0,0 +1,172 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestTypedRepository_Paginate(t *testing.T) {
+	type def struct {
+		name    string
+		page    int64
+		perPage int64
+		query   func(*qry.TypedSelectQuery[model])
+		exp     qry.Pagination[model]
+		expErr  error
+		mockFn  func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name:    "First page",
+			page:    1,
+			perPage: 2,
+			query: func(query *qry.TypedSelectQuery[model]) {
+				query.Condition = qry.Equal("active", true)
+				query.OrderBy = []qry.OrderBy{{Field: "id", Direction: qry.Ascending}}
+			},
+			exp: qry.Pagination[model]{
+				Items:      []*model{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim"}},
+				Page:       1,
+				PerPage:    2,
+				Total:      3,
+				TotalPages: 2,
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM users WHERE active = ?").
+					WithArgs(true).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(3)))
+				db.ExpectQuery("SELECT id, name FROM users WHERE active = ? ORDER BY id ASC LIMIT 2").
+					WithArgs(true).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom").AddRow(int64(2), "Jim"))
+			},
+		},
+		{
+			name:    "Last page",
+			page:    2,
+			perPage: 2,
+			query: func(query *qry.TypedSelectQuery[model]) {
+				query.OrderBy = []qry.OrderBy{{Field: "id", Direction: qry.Ascending}}
+			},
+			exp: qry.Pagination[model]{
+				Items:      []*model{{ID: 3, Name: "Bob"}},
+				Page:       2,
+				PerPage:    2,
+				Total:      3,
+				TotalPages: 2,
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM users").
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(3)))
+				db.ExpectQuery("SELECT id, name FROM users ORDER BY id ASC LIMIT 2 OFFSET 2").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(3), "Bob"))
+			},
+		},
+		{
+			name:    "Grouped",
+			page:    1,
+			perPage: 10,
+			query: func(query *qry.TypedSelectQuery[model]) {
+				query.Fields = []qry.Field{qry.Max("id"), "name"}
+				query.GroupBy = []qry.Field{"name"}
+				query.Having = qry.GreaterThan(qry.Count("*"), 1)
+			},
+			exp: qry.Pagination[model]{
+				Items:      []*model{{ID: 2, Name: "Tom"}},
+				Page:       1,
+				PerPage:    10,
+				Total:      1,
+				TotalPages: 1,
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM (SELECT MAX(id), name FROM users GROUP BY name HAVING COUNT(*) > ?) AS qry_count").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(1)))
+				db.ExpectQuery("SELECT MAX(id), name FROM users GROUP BY name HAVING COUNT(*) > ? LIMIT 10").
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"MAX(id)", "name"}).AddRow(int64(2), "Tom"))
+			},
+		},
+		{
+			name:    "Distinct",
+			page:    1,
+			perPage: 10,
+			query: func(query *qry.TypedSelectQuery[model]) {
+				query.Distinct = true
+				query.OrderBy = []qry.OrderBy{{Field: "id", Direction: qry.Ascending}}
+			},
+			exp: qry.Pagination[model]{
+				Items:      []*model{{ID: 1, Name: "Tom"}},
+				Page:       1,
+				PerPage:    10,
+				Total:      1,
+				TotalPages: 1,
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM (SELECT DISTINCT id, name FROM users) AS qry_count").
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(1)))
+				db.ExpectQuery("SELECT DISTINCT id, name FROM users ORDER BY id ASC LIMIT 10").
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom"))
+			},
+		},
+		{
+			name:    "Past the last page",
+			page:    3,
+			perPage: 2,
+			query: func(query *qry.TypedSelectQuery[model]) {
+			},
+			exp: qry.Pagination[model]{
+				Items:      []*model{},
+				Page:       3,
+				PerPage:    2,
+				Total:      3,
+				TotalPages: 2,
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT COUNT(*) FROM users").
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(int64(3)))
+			},
+		},
+		{
+			name:    "Invalid page",
+			page:    0,
+			perPage: 2,
+			query: func(query *qry.TypedSelectQuery[model]) {
+			},
+			expErr: qry.ErrInvalidQuery,
+			mockFn: func(db sqlmock.Sqlmock) {
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			got, err := newModelRepo(db).PaginateFn(context.Background(), tc.page, tc.perPage, tc.query)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+
+			checkDiff(t, tc.exp, got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -6,13 +6,23 @@ import (
 	"fmt"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"time"
 )
 
 type Repository struct {
-	DB                   *sql.DB
+	DB                   DBTX
 	Table                string
 	LogFn                func(string, []any)
 	StandardSelectFields []Field
+	// Dialect is used to render queries. Defaults to MySQL.
+	Dialect Dialect
+	// QueryTimeout is applied to every query whose context does not already have a deadline, including those
+	// run by a TypedRepository, where a Cursor holds it until closed.
+	// It is not applied by the Repository methods that return rows to the caller, as the rows outlive the call:
+	// Query, QueryFn, QueryRow, QueryRowFn, InsertReturning, UpdateReturning and DeleteReturning.
+	// Give their context a deadline instead.
+	// A zero value means no timeout.
+	QueryTimeout time.Duration
 
 	PreSelectFn func(ctx context.Context, query Query) error
 	PreInsertFn func(ctx context.Context, query Query) error
@@ -20,6 +30,9 @@ type Repository struct {
 	PreDeleteFn func(ctx context.Context, query Query) error
 
 	Tracer trace.Tracer
+
+	// txDepth is the number of transactions and savepoints the repository is within.
+	txDepth int
 }
 
 func (repo Repository) QueryFn(ctx context.Context, queryFn func(*SelectQuery)) (*sql.Rows, error) {
@@ -34,14 +47,18 @@ func (repo Repository) QueryFn(ctx context.Context, queryFn func(*SelectQuery))
 	return repo.Query(ctx, query)
 }
 
-func (repo Repository) Query(ctx context.Context, query SelectQuery) (*sql.Rows, error) {
+// Query executes the given select query and returns the resulting rows.
+// The query is usually a SelectQuery or a CompoundQuery, to which the Repository defaults are applied.
+// The rows remain valid until they are closed, which the caller is responsible for.
+// The QueryTimeout is not applied, so the context should have a deadline.
+func (repo Repository) Query(ctx context.Context, query Query) (*sql.Rows, error) {
 	if repo.Tracer != nil {
 		spanCtx, span := repo.Tracer.Start(ctx, "Query")
 		ctx = spanCtx
 		defer span.End()
 	}
 
-	query = repo.prepareSelectQuery(query)
+	query = repo.prepareQuery(query)
 
 	if repo.PreSelectFn != nil {
 		if err := repo.PreSelectFn(ctx, query); err != nil {
@@ -49,25 +66,7 @@ func (repo Repository) Query(ctx context.Context, query SelectQuery) (*sql.Rows,
 		}
 	}
 
-	sqlQuery, args := query.Build()
-
-	if repo.LogFn != nil {
-		repo.LogFn(sqlQuery, args)
-	}
-
-	stmt, err := repo.DB.Prepare(sqlQuery)
-	if err != nil {
-		return nil, fmt.Errorf("could not prepare query: %w", err)
-	}
-
-	defer stmt.Close()
-
-	rows, err := stmt.Query(args...)
-	if err != nil {
-		return nil, fmt.Errorf("could not execute query: %w", err)
-	}
-
-	return rows, nil
+	return repo.queryRows(ctx, query)
 }
 
 func (repo Repository) QueryRowFn(ctx context.Context, queryFn func(*SelectQuery)) (*sql.Row, error) {
@@ -82,14 +81,18 @@ func (repo Repository) QueryRowFn(ctx context.Context, queryFn func(*SelectQuery
 	return repo.QueryRow(ctx, query)
 }
 
-func (repo Repository) QueryRow(ctx context.Context, query SelectQuery) (*sql.Row, error) {
+// QueryRow executes the given select query and returns the first resulting row.
+// The query is usually a SelectQuery or a CompoundQuery, to which the Repository defaults are applied.
+// The row remains valid until it is scanned.
+// The QueryTimeout is not applied, so the context should have a deadline.
+func (repo Repository) QueryRow(ctx context.Context, query Query) (*sql.Row, error) {
 	if repo.Tracer != nil {
 		spanCtx, span := repo.Tracer.Start(ctx, "QueryRow")
 		ctx = spanCtx
 		defer span.End()
 	}
 
-	query = repo.prepareSelectQuery(query)
+	query = repo.prepareQuery(query)
 
 	if repo.PreSelectFn != nil {
 		if err := repo.PreSelectFn(ctx, query); err != nil {
@@ -97,20 +100,16 @@ func (repo Repository) QueryRow(ctx context.Context, query SelectQuery) (*sql.Ro
 		}
 	}
 
-	sqlQuery, args := query.Build()
+	sqlQuery, args, err := repo.build(query)
+	if err != nil {
+		return nil, fmt.Errorf("could not build query: %w", err)
+	}
 
 	if repo.LogFn != nil {
 		repo.LogFn(sqlQuery, args)
 	}
 
-	stmt, err := repo.DB.Prepare(sqlQuery)
-	if err != nil {
-		return nil, fmt.Errorf("could not prepare query: %w", err)
-	}
-
-	defer stmt.Close()
-
-	row := stmt.QueryRow(args...)
+	row := repo.DB.QueryRowContext(ctx, sqlQuery, args...)
 
 	if err := row.Err(); err != nil {
 		return nil, fmt.Errorf("could not execute query: %w", err)
@@ -209,6 +208,47 @@ func (repo Repository) Insert(ctx context.Context, query InsertQuery) (sql.Resul
 	return repo.Exec(ctx, query)
 }
 
+func (repo Repository) dialect() Dialect {
+	if repo.Dialect == nil {
+		return MySQL
+	}
+	return repo.Dialect
+}
+
+// withQueryTimeout applies the QueryTimeout to the given context if it does not already have a deadline.
+func (repo Repository) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if repo.QueryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, repo.QueryTimeout)
+}
+
+// build renders the given query using the repository Dialect if the query supports it.
+func (repo Repository) build(query Query) (string, []any, error) {
+	if dialectQuery, ok := query.(DialectQuery); ok {
+		return dialectQuery.BuildDialect(repo.dialect())
+	}
+	sqlQuery, args := query.Build()
+	return sqlQuery, args, nil
+}
+
+// prepareQuery applies the Repository defaults to the given query if it is a SelectQuery or CompoundQuery.
+// Other queries are returned as is.
+func (repo Repository) prepareQuery(query Query) Query {
+	switch q := query.(type) {
+	case SelectQuery:
+		return repo.prepareSelectQuery(q)
+	case CompoundQuery:
+		q.Queries = genericMap(q.Queries, repo.prepareSelectQuery)
+		return q
+	default:
+		return query
+	}
+}
+
 func (repo Repository) prepareSelectQuery(query SelectQuery) SelectQuery {
 	if query.Table == "" {
 		query.Table = repo.Table
@@ -240,6 +280,27 @@ func (repo Repository) prepareDeleteQuery(query DeleteQuery) DeleteQuery {
 	return query
 }
 
+// queryRows builds and executes the given query and returns the resulting rows.
+// The QueryTimeout is not applied, as the rows outlive the call. Callers that read the rows themselves
+// should apply it to ctx and cancel it once the rows are closed.
+func (repo Repository) queryRows(ctx context.Context, query Query) (*sql.Rows, error) {
+	sqlQuery, args, err := repo.build(query)
+	if err != nil {
+		return nil, fmt.Errorf("could not build query: %w", err)
+	}
+
+	if repo.LogFn != nil {
+		repo.LogFn(sqlQuery, args)
+	}
+
+	rows, err := repo.DB.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not execute query: %w", err)
+	}
+
+	return rows, nil
+}
+
 func (repo Repository) Exec(ctx context.Context, query Query) (sql.Result, error) {
 	var span trace.Span = nil
 
@@ -248,7 +309,10 @@ func (repo Repository) Exec(ctx context.Context, query Query) (sql.Result, error
 		defer span.End()
 	}
 
-	sqlQuery, args := query.Build()
+	sqlQuery, args, err := repo.build(query)
+	if err != nil {
+		return nil, fmt.Errorf("could not build query: %w", err)
+	}
 
 	if span != nil {
 		span.SetAttributes(attribute.String("query", sqlQuery))
@@ -258,14 +322,17 @@ func (repo Repository) Exec(ctx context.Context, query Query) (sql.Result, error
 		repo.LogFn(sqlQuery, args)
 	}
 
-	stmt, err := repo.DB.Prepare(sqlQuery)
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	stmt, err := repo.DB.PrepareContext(ctx, sqlQuery)
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare query: %w", err)
 	}
 
 	defer stmt.Close()
 
-	result, err := stmt.Exec(args...)
+	result, err := stmt.ExecContext(ctx, args...)
 	if err != nil {
 		return nil, fmt.Errorf("could not execute query: %w", err)
 	}
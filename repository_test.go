@@ -3,9 +3,11 @@ package qry_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/TomWright/qry"
 	"testing"
+	"time"
 )
 
 func TestRepository_QueryRow(t *testing.T) {
@@ -31,8 +33,7 @@ func TestRepository_QueryRow(t *testing.T) {
 				"name": "Tom",
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
 							AddRow(int64(1), "Tom"),
@@ -55,8 +56,7 @@ func TestRepository_QueryRow(t *testing.T) {
 				"username": "Tom",
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT uuid, username FROM user").
-					ExpectQuery().
+				db.ExpectQuery("SELECT uuid, username FROM user").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"uuid", "username"}).
 							AddRow("11112222-3333-4444-5555-666677778888", "Tom"),
@@ -79,8 +79,7 @@ func TestRepository_QueryRow(t *testing.T) {
 			},
 
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users WHERE id = ?").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
@@ -116,8 +115,7 @@ func TestRepository_QueryRow(t *testing.T) {
 			},
 
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users WHERE id = ? ORDER BY name DESC, id ASC LIMIT 5 OFFSET 2").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users WHERE id = ? ORDER BY name DESC, id ASC LIMIT 5 OFFSET 2").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
@@ -180,6 +178,7 @@ func TestRepository_Query(t *testing.T) {
 		name         string
 		table        string
 		selectFields []qry.Field
+		dialect      qry.Dialect
 		query        func() qry.SelectQuery
 		exp          []map[string]any
 		mockFn       func(db sqlmock.Sqlmock)
@@ -204,8 +203,7 @@ func TestRepository_Query(t *testing.T) {
 				},
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
 							AddRow(int64(1), "Tom").
@@ -235,8 +233,7 @@ func TestRepository_Query(t *testing.T) {
 				},
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT uuid, username FROM user").
-					ExpectQuery().
+				db.ExpectQuery("SELECT uuid, username FROM user").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"uuid", "username"}).
 							AddRow("11112222-3333-4444-5555-666677778888", "Tom").
@@ -262,8 +259,7 @@ func TestRepository_Query(t *testing.T) {
 			},
 
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users WHERE id = ?").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
@@ -299,8 +295,7 @@ func TestRepository_Query(t *testing.T) {
 			},
 
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT users.id, users.name, addresses.street FROM users OUTER JOIN addresses ON users.id = addresses.user_id WHERE id = ?").
-					ExpectQuery().
+				db.ExpectQuery("SELECT users.id, users.name, addresses.street FROM users OUTER JOIN addresses ON users.id = addresses.user_id WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"users.id", "users.name", "addresses.street"}).
@@ -309,6 +304,34 @@ func TestRepository_Query(t *testing.T) {
 					RowsWillBeClosed()
 			},
 		},
+		{
+			name:    "Select with postgres dialect",
+			table:   "users",
+			dialect: qry.Postgres,
+			query: func() qry.SelectQuery {
+				query := qry.Select()
+				query.Fields = []qry.Field{"id", "name"}
+				query.Condition = qry.And(qry.Equal("id", 1), qry.Equal("name", "Tom"))
+				query.Limit = 1
+				return query
+			},
+			exp: []map[string]any{
+				{
+					"id":   int64(1),
+					"name": "Tom",
+				},
+			},
+
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("SELECT id, name FROM users WHERE (id = $1 AND name = $2) LIMIT 1").
+					WithArgs(1, "Tom").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(1, "Tom"),
+					).
+					RowsWillBeClosed()
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -333,6 +356,7 @@ func TestRepository_Query(t *testing.T) {
 				DB:                   db,
 				Table:                tc.table,
 				StandardSelectFields: tc.selectFields,
+				Dialect:              tc.dialect,
 				PreSelectFn: func(ctx context.Context, innerQuery qry.Query) error {
 					query = innerQuery.(qry.SelectQuery)
 					return nil
@@ -357,3 +381,285 @@ func TestRepository_Query(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_Exec_Context(t *testing.T) {
+	type def struct {
+		name         string
+		queryTimeout time.Duration
+		ctx          func() (context.Context, context.CancelFunc)
+		delay        time.Duration
+		expErr       bool
+	}
+	tests := []def{
+		{
+			name: "No timeout",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			delay: time.Millisecond * 10,
+		},
+		{
+			name: "Cancelled context",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			expErr: true,
+		},
+		{
+			name:         "Default timeout exceeded",
+			queryTimeout: time.Millisecond * 10,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.Background(), func() {}
+			},
+			delay:  time.Millisecond * 500,
+			expErr: true,
+		},
+		{
+			name:         "Context deadline takes precedence over default timeout",
+			queryTimeout: time.Millisecond,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second*10)
+			},
+			delay: time.Millisecond * 50,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+
+			mock.ExpectPrepare("DELETE FROM users WHERE id = ?").
+				ExpectExec().
+				WithArgs(1).
+				WillDelayFor(tc.delay).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			repo := qry.Repository{
+				DB:           db,
+				Table:        "users",
+				QueryTimeout: tc.queryTimeout,
+			}
+
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			_, err = repo.DeleteFn(ctx, func(query *qry.DeleteQuery) {
+				query.Condition = qry.Equal("id", 1)
+			})
+			if tc.expErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestTypedRepository_QueryTimeout(t *testing.T) {
+	type def struct {
+		name    string
+		delay   time.Duration
+		expErr  bool
+		queryFn func(ctx context.Context, repo qry.TypedRepository[model]) error
+	}
+
+	query := func(ctx context.Context, repo qry.TypedRepository[model]) error {
+		_, err := repo.QueryFn(ctx, func(query *qry.TypedSelectQuery[model]) {})
+		return err
+	}
+	queryRow := func(ctx context.Context, repo qry.TypedRepository[model]) error {
+		_, err := repo.QueryRowFn(ctx, func(query *qry.TypedSelectQuery[model]) {})
+		return err
+	}
+
+	tests := []def{
+		{
+			name:    "Query within timeout",
+			queryFn: query,
+		},
+		{
+			name:    "Query timeout exceeded",
+			delay:   time.Millisecond * 500,
+			expErr:  true,
+			queryFn: query,
+		},
+		{
+			name:    "QueryRow within timeout",
+			queryFn: queryRow,
+		},
+		{
+			name:    "QueryRow timeout exceeded",
+			delay:   time.Millisecond * 500,
+			expErr:  true,
+			queryFn: queryRow,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer func(db *sql.DB) {
+				_ = db.Close()
+			}(db)
+
+			mock.ExpectQuery("SELECT id, name FROM users").
+				WillDelayFor(tc.delay).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom"))
+
+			var scanCtx context.Context
+			repo := newModelRepo(db)
+			repo.QueryTimeout = time.Millisecond * 100
+			repo.PostScanFn = func(ctx context.Context, query qry.Query, target *model) error {
+				scanCtx = ctx
+				return nil
+			}
+
+			err = tc.queryFn(context.Background(), repo)
+			if tc.expErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+			if tc.expErr {
+				return
+			}
+
+			// The timeout must be released once the rows have been read rather than when it expires.
+			if scanCtx == nil || !errors.Is(scanCtx.Err(), context.Canceled) {
+				t.Errorf("expected the query context to be cancelled once the rows were read")
+			}
+		})
+	}
+}
+
+func TestRepository_Query_IgnoresTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillDelayFor(time.Millisecond * 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(1), "Tom"))
+
+	repo := qry.Repository{
+		DB:                   db,
+		Table:                "users",
+		StandardSelectFields: []qry.Field{"id", "name"},
+		QueryTimeout:         time.Millisecond,
+	}
+
+	// The rows outlive the call, so the timeout would otherwise cancel them before they are read.
+	rows, err := repo.Query(context.Background(), qry.Select())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	time.Sleep(time.Millisecond * 10)
+
+	got, err := scanRowsToMapInterface([]qry.Field{"id", "name"}, rows)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiff(t, []map[string]any{{"id": int64(1), "name": "Tom"}}, got)
+}
+
+func TestRepository_Query_RowsOutliveCall(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer func(db *sql.DB) {
+		_ = db.Close()
+	}(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, name FROM users").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "Tom").
+				AddRow(int64(2), "Jim"),
+		).
+		RowsWillBeClosed()
+	mock.ExpectQuery("SELECT id, name FROM users WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "Tom"),
+		).
+		RowsWillBeClosed()
+	mock.ExpectCommit()
+
+	fields := []qry.Field{"id", "name"}
+	repo := qry.Repository{
+		DB:                   db,
+		Table:                "users",
+		StandardSelectFields: fields,
+	}
+
+	err = repo.WithTx(context.Background(), nil, func(txRepo qry.Repository) error {
+		// Rows are read only once Query has returned.
+		rows, err := txRepo.Query(context.Background(), qry.Select())
+		if err != nil {
+			return err
+		}
+		got, err := scanRowsToMapInterface(fields, rows)
+		if err != nil {
+			return err
+		}
+		checkDiff(t, []map[string]any{
+			{"id": int64(1), "name": "Tom"},
+			{"id": int64(2), "name": "Jim"},
+		}, got)
+
+		row, err := txRepo.QueryRowFn(context.Background(), func(query *qry.SelectQuery) {
+			query.Condition = qry.Equal("id", 1)
+		})
+		if err != nil {
+			return err
+		}
+		gotRow, err := scanRowToMapInterface(fields, row)
+		if err != nil {
+			return err
+		}
+		checkDiff(t, map[string]any{"id": int64(1), "name": "Tom"}, gotRow)
+
+		return nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
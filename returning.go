@@ -0,0 +1,273 @@
+package qry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	// FeatureReturning is support for a RETURNING clause on INSERT, UPDATE and DELETE statements.
+	FeatureReturning Feature = "returning"
+	// FeatureOutput is support for an OUTPUT clause on INSERT, UPDATE and DELETE statements.
+	FeatureOutput Feature = "output"
+)
+
+// buildReturning returns the SQL used to return the given fields from an INSERT, UPDATE or DELETE.
+// The output clause is placed before the values or condition of the statement, and the returning
+// clause is appended to the end of it. Only one of them is used, depending on the dialect.
+// source is the pseudo table that holds the affected rows in an OUTPUT clause, such as INSERTED.
+func buildReturning(fields []Field, source string, dialect Dialect) (output string, returning string, err error) {
+	if len(fields) == 0 {
+		return "", "", nil
+	}
+
+	switch {
+	case dialect.Supports(FeatureReturning):
+		return "", fmt.Sprintf(" RETURNING %s", identifiers(dialect, fields)), nil
+	case dialect.Supports(FeatureOutput):
+		outputFields := genericMap(fields, func(field Field) string {
+			return source + "." + identifier(dialect, field)
+		})
+		return fmt.Sprintf(" OUTPUT %s", strings.Join(outputFields, ", ")), "", nil
+	default:
+		return "", "", requireFeature(dialect, FeatureReturning)
+	}
+}
+
+// InsertReturning executes the given insert query and returns the rows produced by its Returning fields.
+// The QueryTimeout is not applied, so the context should have a deadline.
+func (repo Repository) InsertReturning(ctx context.Context, query InsertQuery) (*sql.Rows, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "InsertReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareInsertQuery(query)
+
+	if repo.PreInsertFn != nil {
+		if err := repo.PreInsertFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre insert hook failed: %w", err)
+		}
+	}
+
+	return repo.queryRows(ctx, query)
+}
+
+// UpdateReturning executes the given update query and returns the rows produced by its Returning fields.
+// The QueryTimeout is not applied, so the context should have a deadline.
+func (repo Repository) UpdateReturning(ctx context.Context, query UpdateQuery) (*sql.Rows, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "UpdateReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareUpdateQuery(query)
+
+	if repo.PreUpdateFn != nil {
+		if err := repo.PreUpdateFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre update hook failed: %w", err)
+		}
+	}
+
+	return repo.queryRows(ctx, query)
+}
+
+// DeleteReturning executes the given delete query and returns the rows produced by its Returning fields.
+// The QueryTimeout is not applied, so the context should have a deadline.
+func (repo Repository) DeleteReturning(ctx context.Context, query DeleteQuery) (*sql.Rows, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "DeleteReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareDeleteQuery(query)
+
+	if repo.PreDeleteFn != nil {
+		if err := repo.PreDeleteFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre delete hook failed: %w", err)
+		}
+	}
+
+	return repo.queryRows(ctx, query)
+}
+
+// InsertReturning inserts the targets of the given query and scans the returned fields back into them,
+// matching each returned row to the target at the same position.
+// Returning defaults to StandardSelectFields and is scanned using StandardSelectFieldReferences.
+//
+// Databases do not guarantee the order of the rows returned by a multi-row RETURNING or OUTPUT clause.
+// PostgreSQL and SQLite return them in insert order in practice, but SQL Server does not, so insert a single
+// target at a time when the order cannot be relied upon.
+// Queries that skip conflicting rows, using Ignore or an OnConflict without Update, are rejected since the
+// returned rows could not be matched to their targets, and an error is returned if the number of returned
+// rows differs from the number of targets.
+func (repo TypedRepository[T]) InsertReturning(ctx context.Context, query TypedInsertQuery[T]) error {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "InsertReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareInsertQuery(query)
+	if query.Ignore || (query.OnConflict != nil && len(query.OnConflict.Update) == 0) {
+		return fmt.Errorf("%w: returned rows cannot be matched to targets when conflicting rows are skipped", ErrInvalidQuery)
+	}
+	if query.Returning == nil {
+		query.Returning = repo.StandardSelectFields
+	}
+	if query.ReturningReferences == nil {
+		query.ReturningReferences = repo.StandardSelectFieldReferences
+	}
+
+	if repo.PreInsertFn != nil {
+		if err := repo.PreInsertFn(ctx, query); err != nil {
+			return fmt.Errorf("pre insert hook failed: %w", err)
+		}
+	}
+
+	queries := query.splitByColumns(repo.dialect())
+	if len(queries) == 1 {
+		return repo.insertReturning(ctx, query)
+	}
+
+	return repo.WithTx(ctx, nil, func(txRepo TypedRepository[T]) error {
+		for _, query := range queries {
+			if err := txRepo.insertReturning(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertReturning executes the given query and scans the returned rows into its targets.
+func (repo TypedRepository[T]) insertReturning(ctx context.Context, query TypedInsertQuery[T]) error {
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := repo.Repository.InsertReturning(ctx, query.Prepare())
+	if err != nil {
+		return err
+	}
+
+	return repo.scanReturning(ctx, query, rows, query.Targets, query.ReturningReferences)
+}
+
+// UpdateReturning updates the target of the given query and scans the returned fields back into it.
+// sql.ErrNoRows is returned if no rows were updated.
+// Returning defaults to StandardSelectFields and is scanned using StandardSelectFieldReferences.
+func (repo TypedRepository[T]) UpdateReturning(ctx context.Context, query TypedUpdateQuery[T]) error {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "UpdateReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareUpdateQuery(query)
+	if query.Returning == nil {
+		query.Returning = repo.StandardSelectFields
+	}
+	if query.ReturningReferences == nil {
+		query.ReturningReferences = repo.StandardSelectFieldReferences
+	}
+
+	if repo.PreUpdateFn != nil {
+		if err := repo.PreUpdateFn(ctx, query); err != nil {
+			return fmt.Errorf("pre update hook failed: %w", err)
+		}
+	}
+
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := repo.Repository.UpdateReturning(ctx, query.Prepare())
+	if err != nil {
+		return err
+	}
+
+	return repo.scanReturningRow(ctx, query, rows, query.Target, query.ReturningReferences)
+}
+
+// DeleteReturning deletes the target of the given query and scans the returned fields back into it.
+// sql.ErrNoRows is returned if no rows were deleted.
+// Returning defaults to StandardSelectFields and is scanned using StandardSelectFieldReferences.
+func (repo TypedRepository[T]) DeleteReturning(ctx context.Context, query TypedDeleteQuery[T]) error {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "DeleteReturning")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query = repo.prepareDeleteQuery(query)
+	if query.Returning == nil {
+		query.Returning = repo.StandardSelectFields
+	}
+	if query.ReturningReferences == nil {
+		query.ReturningReferences = repo.StandardSelectFieldReferences
+	}
+
+	if repo.PreDeleteFn != nil {
+		if err := repo.PreDeleteFn(ctx, query); err != nil {
+			return fmt.Errorf("pre delete hook failed: %w", err)
+		}
+	}
+
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
+	rows, err := repo.Repository.DeleteReturning(ctx, query.Prepare())
+	if err != nil {
+		return err
+	}
+
+	return repo.scanReturningRow(ctx, query, rows, query.Target, query.ReturningReferences)
+}
+
+// scanReturning scans each of the given rows into the target at the same index.
+func (repo TypedRepository[T]) scanReturning(ctx context.Context, query Query, rows *sql.Rows, targets []*T, destFn func(*T) []any) error {
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() {
+		if i >= len(targets) {
+			return fmt.Errorf("could not scan row: more rows returned than targets")
+		}
+		if err := repo.scanInto(ctx, query, rows, targets[i], destFn); err != nil {
+			return err
+		}
+		i++
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("could not read rows: %w", err)
+	}
+
+	if i != len(targets) {
+		return fmt.Errorf("could not scan row: %d rows returned for %d targets", i, len(targets))
+	}
+
+	return rows.Close()
+}
+
+// scanReturningRow scans the first of the given rows into the target.
+func (repo TypedRepository[T]) scanReturningRow(ctx context.Context, query Query, rows *sql.Rows, target *T, destFn func(*T) []any) error {
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("could not read rows: %w", err)
+		}
+		return sql.ErrNoRows
+	}
+
+	if err := repo.scanInto(ctx, query, rows, target, destFn); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
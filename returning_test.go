@@ -0,0 +1,330 @@
+package qry_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestBuildDialect_Returning(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+	tests := []def{
+		{
+			name: "Postgres insert",
+			query: qry.InsertQuery{
+				Table:     "users",
+				Fields:    []qry.Field{"name"},
+				Values:    [][]any{{"Tom"}},
+				Returning: []qry.Field{"id", "created_at"},
+			},
+			dialect: qry.Postgres,
+			expStmt: "INSERT INTO users(name) VALUES ($1) RETURNING id, created_at",
+			expArgs: []any{"Tom"},
+		},
+		{
+			name: "SQLite update",
+			query: qry.UpdateQuery{
+				Table:     "users",
+				Values:    map[qry.Field]any{"name": "Tom"},
+				Condition: qry.Equal("id", 1),
+				Returning: []qry.Field{"updated_at"},
+			},
+			dialect: qry.SQLite,
+			expStmt: "UPDATE users SET name = ? WHERE id = ? RETURNING updated_at",
+			expArgs: []any{"Tom", 1},
+		},
+		{
+			name: "SQL Server insert",
+			query: qry.InsertQuery{
+				Table:     "users",
+				Fields:    []qry.Field{"name"},
+				Values:    [][]any{{"Tom"}},
+				Returning: []qry.Field{"id"},
+			},
+			dialect: qry.SQLServer,
+			expStmt: "INSERT INTO users(name) OUTPUT INSERTED.id VALUES (@p1)",
+			expArgs: []any{"Tom"},
+		},
+		{
+			name: "SQL Server update",
+			query: qry.UpdateQuery{
+				Table:     "users",
+				Values:    map[qry.Field]any{"name": "Tom"},
+				Condition: qry.Equal("id", 1),
+				Returning: []qry.Field{"id", "name"},
+			},
+			dialect: qry.SQLServer,
+			expStmt: "UPDATE users SET name = @p1 OUTPUT INSERTED.id, INSERTED.name WHERE id = @p2",
+			expArgs: []any{"Tom", 1},
+		},
+		{
+			name: "SQL Server delete",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Condition: qry.Equal("id", 1),
+				Returning: []qry.Field{"id"},
+			},
+			dialect: qry.SQLServer,
+			expStmt: "DELETE FROM users OUTPUT DELETED.id WHERE id = @p1",
+			expArgs: []any{1},
+		},
+		{
+			name: "MySQL delete",
+			query: qry.DeleteQuery{
+				Table:     "users",
+				Condition: qry.Equal("id", 1),
+				Returning: []qry.Field{"id"},
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrUnsupportedFeature,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
+
+func newReturningRepo(db *sql.DB) qry.TypedRepository[model] {
+	return qry.TypedRepository[model]{
+		Repository: qry.Repository{
+			DB:                   db,
+			Table:                "users",
+			Dialect:              qry.Postgres,
+			StandardSelectFields: []qry.Field{"id", "name"},
+		},
+		StandardSelectFieldReferences: func(target *model) []any {
+			return []any{
+				&target.ID,
+				&target.Name,
+			}
+		},
+		StandardInsertValues: func(target *model) map[qry.Field]any {
+			return map[qry.Field]any{
+				"name": target.Name,
+			}
+		},
+		StandardUpdateValues: func(target *model) map[qry.Field]any {
+			return map[qry.Field]any{
+				"name": target.Name,
+			}
+		},
+		StandardUpdateCondition: func(target *model) qry.Condition {
+			return qry.Equal("id", target.ID)
+		},
+	}
+}
+
+func TestTypedRepository_InsertReturning(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectQuery("INSERT INTO users(name) VALUES ($1), ($2) RETURNING id, name").
+		WithArgs("Tom", "Jim").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name"}).
+				AddRow(int64(1), "Tom").
+				AddRow(int64(2), "Jim"),
+		).
+		RowsWillBeClosed()
+
+	repo := newReturningRepo(db)
+
+	targets := []*model{{Name: "Tom"}, {Name: "Jim"}}
+	query := repo.InsertQuery()
+	query.Targets = targets
+	if err := repo.InsertReturning(context.Background(), query); err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiff(t, []*model{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim"}}, targets)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTypedRepository_InsertReturning_Errors(t *testing.T) {
+	type def struct {
+		name    string
+		queryFn func(query *qry.TypedInsertQuery[model])
+		expErr  error
+		mockFn  func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name:    "Fewer rows than targets",
+			queryFn: func(query *qry.TypedInsertQuery[model]) {},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("INSERT INTO users(name) VALUES ($1), ($2) RETURNING id, name").
+					WithArgs("Tom", "Jim").
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name: "Ignore",
+			queryFn: func(query *qry.TypedInsertQuery[model]) {
+				query.Ignore = true
+			},
+			expErr: qry.ErrInvalidQuery,
+			mockFn: func(db sqlmock.Sqlmock) {},
+		},
+		{
+			name: "On conflict do nothing",
+			queryFn: func(query *qry.TypedInsertQuery[model]) {
+				query.OnConflict = &qry.OnConflict{Fields: []qry.Field{"name"}}
+			},
+			expErr: qry.ErrInvalidQuery,
+			mockFn: func(db sqlmock.Sqlmock) {},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := newReturningRepo(db)
+
+			query := repo.InsertQuery()
+			query.Targets = []*model{{Name: "Tom"}, {Name: "Jim"}}
+			tc.queryFn(&query)
+
+			err = repo.InsertReturning(context.Background(), query)
+			if err == nil {
+				t.Errorf("expected error, got nil")
+			}
+			if tc.expErr != nil && !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTypedRepository_UpdateReturning(t *testing.T) {
+	type def struct {
+		name   string
+		target *model
+		exp    *model
+		expErr error
+		mockFn func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name:   "Updated",
+			target: &model{ID: 1, Name: "Tom"},
+			exp:    &model{ID: 1, Name: "Tom Wright"},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("UPDATE users SET name = $1 WHERE id = $2 RETURNING id, name").
+					WithArgs("Tom", int64(1)).
+					WillReturnRows(
+						sqlmock.NewRows([]string{"id", "name"}).
+							AddRow(int64(1), "Tom Wright"),
+					).
+					RowsWillBeClosed()
+			},
+		},
+		{
+			name:   "Not found",
+			target: &model{ID: 2, Name: "Jim"},
+			exp:    &model{ID: 2, Name: "Jim"},
+			expErr: sql.ErrNoRows,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectQuery("UPDATE users SET name = $1 WHERE id = $2 RETURNING id, name").
+					WithArgs("Jim", int64(2)).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "name"})).
+					RowsWillBeClosed()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := newReturningRepo(db)
+
+			query := repo.UpdateQuery()
+			query.Target = tc.target
+			err = repo.UpdateReturning(context.Background(), query)
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+
+			checkDiff(t, tc.exp, tc.target)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -10,73 +10,162 @@ func Select() SelectQuery {
 
 // SelectQuery is a Query.
 type SelectQuery struct {
-	Fields    []Field
-	Table     string
+	// With is the common table expressions available to the query.
+	With []CTE
+	// Distinct removes duplicate rows from the results.
+	Distinct bool
+	// DistinctOn keeps only the first row of each set of rows with the same values for the given fields.
+	// It requires FeatureDistinctOn.
+	DistinctOn []Field
+	Fields     []Field
+	Table      string
+	// From selects from the results of a subquery rather than from Table.
+	From      *Subquery
 	Condition Condition
 	Join      []Join
+	GroupBy   []Field
+	Having    Condition
 	OrderBy   []OrderBy
 	Limit     int64
 	Offset    int64
+	// Lock locks the selected rows, such as with FOR UPDATE.
+	Lock *Lock
 }
 
 type Join struct {
 	Table string
-	On    Condition
-	Type  string
+	// From joins the results of a subquery rather than Table.
+	From *Subquery
+	On   Condition
+	Type string
 }
 
 func (j Join) Build() (string, []any) {
+	stmt, args, _ := j.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (j Join) buildDialect(dialect Dialect) (string, []any, error) {
 	var kind = j.Type
 	if kind != "" {
 		kind = kind + " "
 	}
 
-	conditionsStmt, conditionArgs := j.On.Build()
+	table, args, err := buildSource(j.Table, j.From, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	conditionsStmt, conditionArgs, err := buildPart(j.On, dialect)
+	if err != nil {
+		return "", nil, err
+	}
 	return fmt.Sprintf(
 		"%sJOIN %s ON %s",
 		kind,
-		j.Table,
+		table,
 		conditionsStmt,
-	), conditionArgs
+	), append(args, conditionArgs...), nil
 }
 
 func (query SelectQuery) Build() (string, []any) {
-	stmt := fmt.Sprintf(
-		"SELECT %s FROM %s",
-		genericJoin(query.Fields, ", "),
-		query.Table,
-	)
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
 
-	args := make([]any, 0)
+func (query SelectQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query SelectQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	limitPrefix, limitSuffix := dialect.Paginate(query.Limit, query.Offset, len(query.OrderBy) > 0)
 
-	if len(query.Join) > 0 {
-		for _, join := range query.Join {
-			joinStmt, joinArgs := join.Build()
-			stmt += fmt.Sprintf(" %s", joinStmt)
-			args = append(args, joinArgs...)
+	distinct := ""
+	if len(query.DistinctOn) > 0 {
+		if err := requireFeature(dialect, FeatureDistinctOn); err != nil {
+			return "", nil, err
 		}
+		distinct = fmt.Sprintf("DISTINCT ON (%s) ", identifiers(dialect, query.DistinctOn))
+	} else if query.Distinct {
+		distinct = "DISTINCT "
+	}
+
+	withStmt, args, err := buildWith(query.With, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	table, tableArgs, err := buildSource(query.Table, query.From, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, tableArgs...)
+
+	stmt := fmt.Sprintf(
+		"%sSELECT %s%s%s FROM %s",
+		withStmt,
+		distinct,
+		limitPrefix,
+		identifiers(dialect, query.Fields),
+		table,
+	)
+
+	joinStmt, joinArgs, err := buildJoins(query.Join, dialect)
+	if err != nil {
+		return "", nil, err
 	}
+	stmt += joinStmt
+	args = append(args, joinArgs...)
 
 	if query.Condition != nil {
-		if conditionsStmt, conditionArgs := query.Condition.Build(); len(conditionsStmt) > 0 {
+		conditionsStmt, conditionArgs, err := buildPart(query.Condition, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(conditionsStmt) > 0 {
 			stmt += fmt.Sprintf(" WHERE %s", conditionsStmt)
 			args = append(args, conditionArgs...)
 		}
 	}
 
-	if len(query.OrderBy) > 0 {
-		stmt += fmt.Sprintf(" ORDER BY %s", genericJoin(query.OrderBy, ", "))
+	if len(query.GroupBy) > 0 {
+		stmt += fmt.Sprintf(" GROUP BY %s", identifiers(dialect, query.GroupBy))
 	}
 
-	if query.Limit > 0 {
-		stmt += fmt.Sprintf(" LIMIT %d", query.Limit)
+	if query.Having != nil {
+		havingStmt, havingArgs, err := buildPart(query.Having, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(havingStmt) > 0 {
+			stmt += fmt.Sprintf(" HAVING %s", havingStmt)
+			args = append(args, havingArgs...)
+		}
 	}
 
-	if query.Offset > 0 {
-		stmt += fmt.Sprintf(" OFFSET %d", query.Offset)
+	if len(query.OrderBy) > 0 {
+		stmt += fmt.Sprintf(" ORDER BY %s", buildOrderBy(query.OrderBy, dialect))
 	}
 
-	return stmt, args
+	stmt += limitSuffix
+
+	if query.Lock != nil {
+		lockStmt, err := query.Lock.buildDialect(dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		stmt += lockStmt
+	}
+
+	return stmt, args, nil
+}
+
+// buildSource returns the table to read from, which is the given subquery if it is set.
+func buildSource(table string, subquery *Subquery, dialect Dialect) (string, []any, error) {
+	if subquery == nil {
+		return identifier(dialect, table), make([]any, 0), nil
+	}
+	return subquery.buildDialect(dialect)
 }
 
 type TypedSelectQuery[T any] struct {
@@ -91,3 +180,11 @@ func (query TypedSelectQuery[T]) Prepare() SelectQuery {
 func (query TypedSelectQuery[T]) Build() (string, []any) {
 	return query.Prepare().Build()
 }
+
+func (query TypedSelectQuery[T]) BuildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().BuildDialect(dialect)
+}
+
+func (query TypedSelectQuery[T]) buildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().buildDialect(dialect)
+}
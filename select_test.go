@@ -0,0 +1,79 @@
+package qry_test
+
+import (
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestSelectQuery_Build(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.SelectQuery
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name: "Group by with having",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{
+					"user_id",
+					qry.Count("*").As("total"),
+					qry.Sum("amount"),
+				},
+				Table:     "orders",
+				Condition: qry.Equal("status", "paid"),
+				GroupBy:   []qry.Field{"user_id"},
+				Having:    qry.GreaterThan(qry.Count("*"), 5),
+				OrderBy: []qry.OrderBy{
+					{Field: qry.Sum("amount"), Direction: qry.Descending},
+				},
+				Limit: 10,
+			},
+			expStmt: "SELECT user_id, COUNT(*) AS total, SUM(amount) FROM orders WHERE status = ? GROUP BY user_id HAVING COUNT(*) > ? ORDER BY SUM(amount) DESC LIMIT 10",
+			expArgs: []any{"paid", 5},
+		},
+		{
+			name: "Aggregates",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{
+					qry.CountDistinct("user_id"),
+					qry.Avg("amount"),
+					qry.Min("amount"),
+					qry.Max("amount"),
+				},
+				Table: "orders",
+			},
+			expStmt: "SELECT COUNT(DISTINCT user_id), AVG(amount), MIN(amount), MAX(amount) FROM orders",
+			expArgs: []any{},
+		},
+		{
+			name: "Distinct",
+			query: qry.SelectQuery{
+				Distinct:  true,
+				Fields:    []qry.Field{"user_id"},
+				Table:     "orders",
+				Condition: qry.Equal("status", "paid"),
+			},
+			expStmt: "SELECT DISTINCT user_id FROM orders WHERE status = ?",
+			expArgs: []any{"paid"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := tc.query.Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package qry
+
+import (
+	"fmt"
+	"github.com/TomWright/qry/internal/dbtag"
+	"reflect"
+	"sync"
+)
+
+// structColumn is a struct field that is mapped to a column.
+type structColumn struct {
+	tag   dbtag.Tag
+	index []int
+	typ   reflect.Type
+}
+
+// structMapping describes how a struct type maps to columns.
+type structMapping struct {
+	columns    []structColumn
+	primaryKey []structColumn
+}
+
+// structMappings caches the structMapping of each type it has been requested for.
+var structMappings sync.Map
+
+// NewTypedRepositoryFromTags returns a TypedRepository whose standard select fields, field references,
+// insert values, update values, primary key values and update and delete conditions are derived from the `db` struct tags of T.
+//
+// Each tag is made up of the column name followed by any of the options pk, omitempty, readonly and
+// autoincrement, for example `db:"id,pk,autoincrement"`. Fields without a tag are ignored, and the fields of
+// embedded structs without a tag are mapped as if they belong to T. The fields of a nil embedded struct pointer
+// are read as zero values, and the pointer is allocated when rows are scanned into it.
+//
+// Any standard select fields already set on the given Repository are left as is.
+func NewTypedRepositoryFromTags[T any](repo Repository) (TypedRepository[T], error) {
+	mapping, err := structMappingFor(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return TypedRepository[T]{}, err
+	}
+
+	if repo.StandardSelectFields == nil {
+		repo.StandardSelectFields = mapping.fields()
+	}
+
+	typedRepo := TypedRepository[T]{
+		Repository: repo,
+		StandardSelectFieldReferences: func(target *T) []any {
+			return mapping.references(reflect.ValueOf(target).Elem())
+		},
+		StandardInsertValues: func(target *T) map[Field]any {
+			return mapping.values(reflect.ValueOf(target).Elem(), dbtag.Tag.Insertable)
+		},
+		StandardUpdateValues: func(target *T) map[Field]any {
+			return mapping.values(reflect.ValueOf(target).Elem(), dbtag.Tag.Updatable)
+		},
+	}
+
+	if len(mapping.primaryKey) > 0 {
+		typedRepo.StandardUpdateCondition = func(target *T) Condition {
+			return mapping.primaryKeyCondition(reflect.ValueOf(target).Elem())
+		}
+		typedRepo.StandardDeleteCondition = typedRepo.StandardUpdateCondition
+		typedRepo.StandardPrimaryKeyValues = func(target *T) map[Field]any {
+			return mapping.primaryKeyValues(reflect.ValueOf(target).Elem())
+		}
+	}
+
+	return typedRepo, nil
+}
+
+// structMappingFor returns the structMapping for the given type, using the cache where possible.
+func structMappingFor(t reflect.Type) (*structMapping, error) {
+	if cached, ok := structMappings.Load(t); ok {
+		return cached.(*structMapping), nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("could not map %s: expected a struct", t)
+	}
+
+	mapping := &structMapping{}
+	if err := mapping.addStruct(t, nil); err != nil {
+		return nil, fmt.Errorf("could not map %s: %w", t, err)
+	}
+
+	seen := make(map[string]bool, len(mapping.columns))
+	for _, column := range mapping.columns {
+		if seen[column.tag.Name] {
+			return nil, fmt.Errorf("could not map %s: duplicate column %q", t, column.tag.Name)
+		}
+		seen[column.tag.Name] = true
+	}
+
+	cached, _ := structMappings.LoadOrStore(t, mapping)
+	return cached.(*structMapping), nil
+}
+
+func (mapping *structMapping) addStruct(t reflect.Type, parentIndex []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+
+		index := make([]int, len(parentIndex)+1)
+		copy(index, parentIndex)
+		index[len(parentIndex)] = i
+
+		tagValue, hasTag := structField.Tag.Lookup(dbtag.Key)
+
+		if structField.Anonymous && !hasTag {
+			embeddedType := structField.Type
+			if embeddedType.Kind() == reflect.Pointer && embeddedType.Elem().Kind() == reflect.Struct {
+				if !structField.IsExported() {
+					// The pointer could not be allocated when scanning into it.
+					return fmt.Errorf("field %s: embedded struct pointers must be exported", structField.Name)
+				}
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				if err := mapping.addStruct(embeddedType, index); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		tag, ok, err := dbtag.Parse(tagValue)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", structField.Name, err)
+		}
+		if !ok {
+			continue
+		}
+		if !structField.IsExported() {
+			return fmt.Errorf("field %s: tagged fields must be exported", structField.Name)
+		}
+
+		column := structColumn{
+			tag:   tag,
+			index: index,
+			typ:   structField.Type,
+		}
+		mapping.columns = append(mapping.columns, column)
+		if tag.PrimaryKey {
+			mapping.primaryKey = append(mapping.primaryKey, column)
+		}
+	}
+	return nil
+}
+
+// field returns the field of the column within the given struct value.
+// A field within a nil embedded struct pointer is returned as its zero value.
+func (column structColumn) field(v reflect.Value) reflect.Value {
+	field, err := v.FieldByIndexErr(column.index)
+	if err != nil {
+		return reflect.Zero(column.typ)
+	}
+	return field
+}
+
+// allocField returns the field of the column within the given struct value, allocating any nil embedded
+// struct pointers on the way to it.
+func (column structColumn) allocField(v reflect.Value) reflect.Value {
+	for i, x := range column.index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// fields returns every mapped column.
+func (mapping *structMapping) fields() []Field {
+	return genericMap(mapping.columns, func(column structColumn) Field {
+		return Field(column.tag.Name)
+	})
+}
+
+// references returns a pointer to each mapped field of the given struct value.
+func (mapping *structMapping) references(v reflect.Value) []any {
+	return genericMap(mapping.columns, func(column structColumn) any {
+		return column.allocField(v).Addr().Interface()
+	})
+}
+
+// values returns the value of each mapped field that the include func accepts.
+func (mapping *structMapping) values(v reflect.Value, include func(tag dbtag.Tag, zero bool) bool) map[Field]any {
+	values := make(map[Field]any, len(mapping.columns))
+	for _, column := range mapping.columns {
+		fieldValue := column.field(v)
+		if include(column.tag, fieldValue.IsZero()) {
+			values[Field(column.tag.Name)] = fieldValue.Interface()
+		}
+	}
+	return values
+}
+
+// primaryKeyValues returns the value of each primary key field of the given struct value.
+func (mapping *structMapping) primaryKeyValues(v reflect.Value) map[Field]any {
+	values := make(map[Field]any, len(mapping.primaryKey))
+	for _, column := range mapping.primaryKey {
+		values[Field(column.tag.Name)] = column.field(v).Interface()
+	}
+	return values
+}
+
+// primaryKeyCondition returns a Condition matching the primary key of the given struct value.
+func (mapping *structMapping) primaryKeyCondition(v reflect.Value) Condition {
+	conditions := genericMap(mapping.primaryKey, func(column structColumn) Condition {
+		return Equal(Field(column.tag.Name), column.field(v).Interface())
+	})
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return And(conditions...)
+}
@@ -0,0 +1,313 @@
+package qry_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+	"time"
+)
+
+type timestamps struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedAt time.Time `db:"updated_at,omitempty"`
+}
+
+type taggedUser struct {
+	ID       int64  `db:"id,pk,autoincrement"`
+	Name     string `db:"name"`
+	Nickname string `db:"nickname,omitempty"`
+	Ignored  string
+	Skipped  string `db:"-"`
+	timestamps
+}
+
+// Audit is embedded by pointer in auditedUser.
+type Audit struct {
+	CreatedBy string `db:"created_by"`
+	Note      string `db:"note,omitempty"`
+}
+
+type auditedUser struct {
+	ID int64 `db:"id,pk"`
+	*Audit
+}
+
+func TestNewTypedRepositoryFromTags(t *testing.T) {
+	repo, err := qry.NewTypedRepositoryFromTags[taggedUser](qry.Repository{
+		Table: "users",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	user := &taggedUser{
+		ID:   1,
+		Name: "Tom",
+		timestamps: timestamps{
+			CreatedAt: createdAt,
+		},
+	}
+
+	t.Run("Select fields", func(t *testing.T) {
+		checkDiff(t, []qry.Field{"id", "name", "nickname", "created_at", "updated_at"}, repo.StandardSelectFields)
+	})
+
+	t.Run("Select field references", func(t *testing.T) {
+		target := &taggedUser{}
+		checkDiff(t, []any{
+			&target.ID,
+			&target.Name,
+			&target.Nickname,
+			&target.CreatedAt,
+			&target.UpdatedAt,
+		}, repo.StandardSelectFieldReferences(target))
+	})
+
+	t.Run("Insert values", func(t *testing.T) {
+		checkDiff(t, map[qry.Field]any{
+			"id":   int64(1),
+			"name": "Tom",
+		}, repo.StandardInsertValues(user))
+
+		checkDiff(t, map[qry.Field]any{
+			"name":     "Jim",
+			"nickname": "Jimbo",
+		}, repo.StandardInsertValues(&taggedUser{Name: "Jim", Nickname: "Jimbo"}))
+	})
+
+	t.Run("Update values", func(t *testing.T) {
+		checkDiff(t, map[qry.Field]any{
+			"name": "Tom",
+		}, repo.StandardUpdateValues(user))
+	})
+
+	t.Run("Update and delete conditions", func(t *testing.T) {
+		checkDiff(t, qry.Equal("id", int64(1)), repo.StandardUpdateCondition(user))
+		checkDiff(t, qry.Equal("id", int64(1)), repo.StandardDeleteCondition(user))
+	})
+
+	t.Run("Primary key values", func(t *testing.T) {
+		checkDiff(t, map[qry.Field]any{"id": int64(1)}, repo.StandardPrimaryKeyValues(user))
+	})
+}
+
+func TestNewTypedRepositoryFromTags_Query(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	createdAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery("SELECT id, name, nickname, created_at, updated_at FROM users WHERE id = ?").
+		WithArgs(1).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"id", "name", "nickname", "created_at", "updated_at"}).
+				AddRow(int64(1), "Tom", "", createdAt, createdAt),
+		).
+		RowsWillBeClosed()
+
+	repo, err := qry.NewTypedRepositoryFromTags[taggedUser](qry.Repository{
+		DB:    db,
+		Table: "users",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	got, err := repo.QueryRowFn(context.Background(), func(query *qry.TypedSelectQuery[taggedUser]) {
+		query.Condition = qry.Equal("id", 1)
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiff(t, &taggedUser{
+		ID:   1,
+		Name: "Tom",
+		timestamps: timestamps{
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		},
+	}, got)
+}
+
+func TestNewTypedRepositoryFromTags_Errors(t *testing.T) {
+	type duplicate struct {
+		A string `db:"name"`
+		B string `db:"name"`
+	}
+	type unknownOption struct {
+		A string `db:"name,unique"`
+	}
+
+	if _, err := qry.NewTypedRepositoryFromTags[duplicate](qry.Repository{}); err == nil {
+		t.Errorf("expected error for duplicate column")
+	}
+	if _, err := qry.NewTypedRepositoryFromTags[unknownOption](qry.Repository{}); err == nil {
+		t.Errorf("expected error for unknown option")
+	}
+	if _, err := qry.NewTypedRepositoryFromTags[string](qry.Repository{}); err == nil {
+		t.Errorf("expected error for non struct type")
+	}
+	type unexportedEmbeddedPointer struct {
+		*timestamps
+	}
+	if _, err := qry.NewTypedRepositoryFromTags[unexportedEmbeddedPointer](qry.Repository{}); err == nil {
+		t.Errorf("expected error for unexported embedded struct pointer")
+	}
+}
+
+func TestNewTypedRepositoryFromTags_EmbeddedPointer(t *testing.T) {
+	repo, err := qry.NewTypedRepositoryFromTags[auditedUser](qry.Repository{})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	checkDiffMsg(t, []qry.Field{"id", "created_by", "note"}, repo.StandardSelectFields, "invalid select fields")
+
+	unset := &auditedUser{ID: 1}
+	checkDiffMsg(t, map[qry.Field]any{"id": int64(1), "created_by": ""}, repo.StandardInsertValues(unset), "invalid insert values of nil pointer")
+	if unset.Audit != nil {
+		t.Errorf("expected reading values to leave the embedded pointer nil")
+	}
+
+	set := &auditedUser{ID: 2, Audit: &Audit{CreatedBy: "Tom", Note: "hi"}}
+	checkDiffMsg(t, map[qry.Field]any{"created_by": "Tom", "note": "hi"}, repo.StandardUpdateValues(set), "invalid update values")
+
+	references := repo.StandardSelectFieldReferences(unset)
+	if unset.Audit == nil {
+		t.Errorf("expected field references to allocate the embedded pointer")
+		return
+	}
+	*references[1].(*string) = "Jim"
+	checkDiffMsg(t, "Jim", unset.CreatedBy, "invalid scanned value")
+}
+
+func TestNewTypedRepositoryFromTags_InsertOmitEmpty(t *testing.T) {
+	repo, err := qry.NewTypedRepositoryFromTags[taggedUser](qry.Repository{
+		Table: "users",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	type def struct {
+		name    string
+		targets []*taggedUser
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name:    "Later target has an extra column",
+			targets: []*taggedUser{{Name: "Tom"}, {Name: "Jim", Nickname: "Jimbo"}},
+			expStmt: "INSERT INTO users(name, nickname) VALUES (?, DEFAULT), (?, ?)",
+			expArgs: []any{"Tom", "Jim", "Jimbo"},
+		},
+		{
+			name:    "Earlier target has an extra column",
+			targets: []*taggedUser{{ID: 1, Name: "Jim", Nickname: "Jimbo"}, {Name: "Tom"}},
+			expStmt: "INSERT INTO users(id, name, nickname) VALUES (?, ?, ?), (DEFAULT, ?, DEFAULT)",
+			expArgs: []any{int64(1), "Jim", "Jimbo", "Tom"},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			query := repo.InsertQuery()
+			query.Table = "users"
+			query.Values = repo.StandardInsertValues
+			query.Targets = tc.targets
+
+			gotStmt, gotArgs, err := query.BuildDialect(qry.MySQL)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
+
+func TestNewTypedRepositoryFromTags_InsertOmitEmptyWithoutDefault(t *testing.T) {
+	insertFns := map[string]func(repo qry.TypedRepository[taggedUser], targets []*taggedUser) (int64, error){
+		"Insert": func(repo qry.TypedRepository[taggedUser], targets []*taggedUser) (int64, error) {
+			query := repo.InsertQuery()
+			query.Targets = targets
+			result, err := repo.Insert(context.Background(), query)
+			if err != nil {
+				return 0, err
+			}
+			return result.RowsAffected()
+		},
+		"InsertBatch": func(repo qry.TypedRepository[taggedUser], targets []*taggedUser) (int64, error) {
+			return repo.InsertBatch(context.Background(), targets, qry.InsertBatchOptions{})
+		},
+	}
+
+	for name, insertFn := range insertFns {
+		insertFn := insertFn
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			mock.ExpectBegin()
+			mock.ExpectPrepare("INSERT INTO users(name) VALUES (?), (?)").
+				ExpectExec().
+				WithArgs("Tom", "Bob").
+				WillReturnResult(sqlmock.NewResult(0, 2))
+			mock.ExpectPrepare("INSERT INTO users(name, nickname) VALUES (?, ?)").
+				ExpectExec().
+				WithArgs("Jim", "Jimbo").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			repo, err := qry.NewTypedRepositoryFromTags[taggedUser](qry.Repository{
+				DB:      db,
+				Table:   "users",
+				Dialect: qry.SQLite,
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			got, err := insertFn(repo, []*taggedUser{{Name: "Tom"}, {Name: "Jim", Nickname: "Jimbo"}, {Name: "Bob"}})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			checkDiff(t, int64(3), got)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package qry
+
+import (
+	"fmt"
+)
+
+// rowQuery is implemented by queries that return rows, and so can be used as a subquery.
+type rowQuery interface {
+	Query
+	dialectBuilder
+	returnsRows()
+}
+
+func (query SelectQuery) returnsRows() {}
+
+// Subquery is a query that is used as a table, such as within a FROM or JOIN clause.
+type Subquery struct {
+	Query Query
+	// Alias is the name the results of the query are referred to by. It is required.
+	Alias string
+}
+
+// As returns a Subquery that can be used as a table with the given alias.
+func As(query Query, alias string) *Subquery {
+	return &Subquery{
+		Query: query,
+		Alias: alias,
+	}
+}
+
+func (subquery *Subquery) Build() (string, []any) {
+	stmt, args, _ := subquery.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (subquery *Subquery) buildDialect(dialect Dialect) (string, []any, error) {
+	if subquery.Alias == "" {
+		return "", nil, fmt.Errorf("%w: subquery requires an alias", ErrInvalidQuery)
+	}
+	stmt, args, err := buildPart(subquery.Query, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("(%s) AS %s", stmt, identifier(dialect, subquery.Alias)), args, nil
+}
+
+// ExistsCondition is a Condition that checks whether a subquery returns any rows.
+type ExistsCondition struct {
+	Query Query
+	Not   bool
+}
+
+// Build returns an SQL statement and the related args.
+func (condition *ExistsCondition) Build() (string, []any) {
+	stmt, args, _ := condition.buildDialect(MySQL)
+	return stmt, args
+}
+
+func (condition *ExistsCondition) buildDialect(dialect Dialect) (string, []any, error) {
+	stmt, args, err := buildPart(condition.Query, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	operator := "EXISTS"
+	if condition.Not {
+		operator = "NOT EXISTS"
+	}
+	return fmt.Sprintf("%s (%s)", operator, stmt), args, nil
+}
@@ -0,0 +1,170 @@
+package qry_test
+
+import (
+	"errors"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestSubquery(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.DialectQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	activeUserIDs := qry.SelectQuery{
+		Fields:    []qry.Field{"id"},
+		Table:     "users",
+		Condition: qry.Equal("active", true),
+	}
+	userOrders := qry.SelectQuery{
+		Fields:    []qry.Field{"1"},
+		Table:     "orders",
+		Condition: qry.And(&qry.RawCondition{SQL: "orders.user_id = users.id"}, qry.GreaterThan("total", 100)),
+	}
+
+	tests := []def{
+		{
+			name: "In subquery",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "orders",
+				Condition: qry.And(qry.Equal("status", "paid"), qry.In("user_id", activeUserIDs)),
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM orders WHERE (status = $1 AND user_id IN (SELECT id FROM users WHERE active = $2))",
+			expArgs: []any{"paid", true},
+		},
+		{
+			name: "Not in subquery",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "orders",
+				Condition: qry.NotIn("user_id", activeUserIDs),
+			},
+			dialect: qry.MySQL,
+			expStmt: "SELECT id FROM orders WHERE user_id NOT IN (SELECT id FROM users WHERE active = ?)",
+			expArgs: []any{true},
+		},
+		{
+			name: "Exists",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "users",
+				Condition: qry.Exists(userOrders),
+			},
+			dialect: qry.MySQL,
+			expStmt: "SELECT id FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE (orders.user_id = users.id AND total > ?))",
+			expArgs: []any{100},
+		},
+		{
+			name: "Not exists",
+			query: qry.SelectQuery{
+				Fields:    []qry.Field{"id"},
+				Table:     "users",
+				Condition: qry.NotExists(userOrders),
+			},
+			dialect: qry.MySQL,
+			expStmt: "SELECT id FROM users WHERE NOT EXISTS (SELECT 1 FROM orders WHERE (orders.user_id = users.id AND total > ?))",
+			expArgs: []any{100},
+		},
+		{
+			name: "Scalar comparison",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id"},
+				Table:  "orders",
+				Condition: qry.GreaterThan("total", qry.SelectQuery{
+					Fields:    []qry.Field{qry.Avg("total")},
+					Table:     "orders",
+					Condition: qry.Equal("status", "paid"),
+				}),
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT id FROM orders WHERE total > (SELECT AVG(total) FROM orders WHERE status = $1)",
+			expArgs: []any{"paid"},
+		},
+		{
+			name: "Nested dialect rendering",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id"},
+				Table:  "users",
+				Condition: qry.Not(qry.And(qry.Equal("name", "Tom"), qry.Equal("id", qry.SelectQuery{
+					Fields:  []qry.Field{"user_id"},
+					Table:   "orders",
+					OrderBy: []qry.OrderBy{{Field: "total", Direction: qry.Descending}},
+					Limit:   1,
+				}))),
+			},
+			dialect: qry.SQLServer,
+			expStmt: "SELECT id FROM users WHERE NOT ((name = @p1 AND id = (SELECT TOP 1 user_id FROM orders ORDER BY total DESC)))",
+			expArgs: []any{"Tom"},
+		},
+		{
+			name: "From and join subqueries",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"u.id", "o.total"},
+				From: qry.As(qry.SelectQuery{
+					Fields:    []qry.Field{"id"},
+					Table:     "users",
+					Condition: qry.Equal("active", true),
+				}, "u"),
+				Join: []qry.Join{
+					{
+						From: qry.As(qry.SelectQuery{
+							Fields:    []qry.Field{"user_id", qry.Sum("total").As("total")},
+							Table:     "orders",
+							Condition: qry.Equal("status", "paid"),
+							GroupBy:   []qry.Field{"user_id"},
+						}, "o"),
+						On:   &qry.RawCondition{SQL: "o.user_id = u.id"},
+						Type: "LEFT",
+					},
+				},
+				Condition: qry.GreaterThan("o.total", 100),
+			},
+			dialect: qry.Postgres,
+			expStmt: "SELECT u.id, o.total FROM (SELECT id FROM users WHERE active = $1) AS u LEFT JOIN (SELECT user_id, SUM(total) AS total FROM orders WHERE status = $2 GROUP BY user_id) AS o ON o.user_id = u.id WHERE o.total > $3",
+			expArgs: []any{true, "paid", 100},
+		},
+		{
+			name: "Missing alias",
+			query: qry.SelectQuery{
+				Fields: []qry.Field{"id"},
+				From:   qry.As(activeUserIDs, ""),
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package qry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// DBTX is the database handle used by a Repository to execute queries.
+// It is satisfied by *sql.DB, *sql.Tx and *sql.Conn.
+type DBTX interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+var (
+	_ DBTX = (*sql.DB)(nil)
+	_ DBTX = (*sql.Tx)(nil)
+	_ DBTX = (*sql.Conn)(nil)
+)
+
+// txBeginner is implemented by database handles that can start a transaction, such as *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// ErrTxNotSupported is returned by WithTx when the Repository DB can neither begin a transaction
+// nor create a savepoint.
+var ErrTxNotSupported = errors.New("database handle does not support transactions")
+
+// WithTx calls fn with a copy of the Repository that executes every query within a transaction.
+// The transaction is committed if fn returns nil, and is rolled back if fn returns an error or panics.
+// If the Repository is already within a transaction, a savepoint is used instead so that only the
+// work done by fn is rolled back. opts are ignored when a savepoint is used.
+func (repo Repository) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(txRepo Repository) error) error {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "WithTx")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	switch db := repo.DB.(type) {
+	case *sql.Tx:
+		return repo.withSavepoint(ctx, db, fn)
+	case txBeginner:
+		tx, err := db.BeginTx(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("could not begin transaction: %w", err)
+		}
+
+		txRepo := repo
+		txRepo.DB = tx
+		txRepo.txDepth = 1
+
+		return runTx(
+			func() error {
+				return fn(txRepo)
+			},
+			tx.Commit,
+			tx.Rollback,
+		)
+	default:
+		return ErrTxNotSupported
+	}
+}
+
+func (repo Repository) withSavepoint(ctx context.Context, tx *sql.Tx, fn func(txRepo Repository) error) error {
+	name := fmt.Sprintf("qry_savepoint_%d", repo.txDepth)
+	create, release, rollback := repo.dialect().Savepoint(name)
+
+	if _, err := tx.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("could not create savepoint: %w", err)
+	}
+
+	txRepo := repo
+	txRepo.txDepth++
+
+	return runTx(
+		func() error {
+			return fn(txRepo)
+		},
+		func() error {
+			if release == "" {
+				return nil
+			}
+			_, err := tx.ExecContext(ctx, release)
+			return err
+		},
+		func() error {
+			_, err := tx.ExecContext(ctx, rollback)
+			return err
+		},
+	)
+}
+
+// runTx calls fn and then commits on success, or rolls back if fn returns an error or panics.
+// Panics are re-raised once the rollback has completed.
+func runTx(fn func() error, commit func() error, rollback func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(); err != nil {
+		if rollbackErr := rollback(); rollbackErr != nil {
+			return fmt.Errorf("could not rollback transaction: %v: %w", rollbackErr, err)
+		}
+		return err
+	}
+
+	if err := commit(); err != nil {
+		return fmt.Errorf("could not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// WithTx calls fn with a copy of the TypedRepository that executes every query within a transaction.
+// See Repository.WithTx for details.
+func (repo TypedRepository[T]) WithTx(ctx context.Context, opts *sql.TxOptions, fn func(txRepo TypedRepository[T]) error) error {
+	return repo.Repository.WithTx(ctx, opts, func(txRepo Repository) error {
+		typedTxRepo := repo
+		typedTxRepo.Repository = txRepo
+		return fn(typedTxRepo)
+	})
+}
@@ -0,0 +1,213 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestRepository_WithTx(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	insert := func(txRepo qry.Repository, name string) error {
+		_, err := txRepo.InsertFn(context.Background(), func(query *qry.InsertQuery) {
+			query.Fields = []qry.Field{"name"}
+			query.Values = [][]any{{name}}
+		})
+		return err
+	}
+
+	expectInsert := func(db sqlmock.Sqlmock, name string) {
+		db.ExpectPrepare("INSERT INTO users(name) VALUES (?)").
+			ExpectExec().
+			WithArgs(name).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+	}
+
+	type def struct {
+		name     string
+		fn       func(txRepo qry.Repository) error
+		expErr   error
+		expPanic bool
+		mockFn   func(db sqlmock.Sqlmock)
+	}
+	tests := []def{
+		{
+			name: "Commit",
+			fn: func(txRepo qry.Repository) error {
+				return insert(txRepo, "Tom")
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				expectInsert(db, "Tom")
+				db.ExpectCommit()
+			},
+		},
+		{
+			name: "Rollback on error",
+			fn: func(txRepo qry.Repository) error {
+				if err := insert(txRepo, "Tom"); err != nil {
+					return err
+				}
+				return errFailed
+			},
+			expErr: errFailed,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				expectInsert(db, "Tom")
+				db.ExpectRollback()
+			},
+		},
+		{
+			name: "Rollback on panic",
+			fn: func(txRepo qry.Repository) error {
+				panic("boom")
+			},
+			expPanic: true,
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				db.ExpectRollback()
+			},
+		},
+		{
+			name: "Nested savepoint released",
+			fn: func(txRepo qry.Repository) error {
+				if err := insert(txRepo, "Tom"); err != nil {
+					return err
+				}
+				return txRepo.WithTx(context.Background(), nil, func(nestedRepo qry.Repository) error {
+					return insert(nestedRepo, "Jim")
+				})
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				expectInsert(db, "Tom")
+				db.ExpectExec("SAVEPOINT qry_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectInsert(db, "Jim")
+				db.ExpectExec("RELEASE SAVEPOINT qry_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				db.ExpectCommit()
+			},
+		},
+		{
+			name: "Nested savepoint rolled back",
+			fn: func(txRepo qry.Repository) error {
+				if err := insert(txRepo, "Tom"); err != nil {
+					return err
+				}
+				err := txRepo.WithTx(context.Background(), nil, func(nestedRepo qry.Repository) error {
+					return nestedRepo.WithTx(context.Background(), nil, func(nestedRepo qry.Repository) error {
+						if err := insert(nestedRepo, "Jim"); err != nil {
+							return err
+						}
+						return errFailed
+					})
+				})
+				if !errors.Is(err, errFailed) {
+					return err
+				}
+				return nil
+			},
+			mockFn: func(db sqlmock.Sqlmock) {
+				db.ExpectBegin()
+				expectInsert(db, "Tom")
+				db.ExpectExec("SAVEPOINT qry_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				db.ExpectExec("SAVEPOINT qry_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				expectInsert(db, "Jim")
+				db.ExpectExec("ROLLBACK TO SAVEPOINT qry_savepoint_2").WillReturnResult(sqlmock.NewResult(0, 0))
+				db.ExpectExec("ROLLBACK TO SAVEPOINT qry_savepoint_1").WillReturnResult(sqlmock.NewResult(0, 0))
+				db.ExpectCommit()
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			if err != nil {
+				t.Errorf("could not init db mock")
+				return
+			}
+
+			defer db.Close()
+
+			tc.mockFn(mock)
+
+			repo := qry.Repository{
+				DB:    db,
+				Table: "users",
+			}
+
+			func() {
+				defer func() {
+					p := recover()
+					if tc.expPanic != (p != nil) {
+						t.Errorf("expected panic %v, got %v", tc.expPanic, p)
+					}
+				}()
+
+				err = repo.WithTx(context.Background(), nil, tc.fn)
+			}()
+
+			if !errors.Is(err, tc.expErr) {
+				t.Errorf("expected error %v, got %v", tc.expErr, err)
+				return
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTypedRepository_WithTx(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE users SET name = ? WHERE id = ?").
+		ExpectExec().
+		WithArgs("Tom", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := qry.TypedRepository[model]{
+		Repository: qry.Repository{
+			DB:    db,
+			Table: "users",
+		},
+		StandardUpdateValues: func(target *model) map[qry.Field]any {
+			return map[qry.Field]any{
+				"name": target.Name,
+			}
+		},
+		StandardUpdateCondition: func(target *model) qry.Condition {
+			return qry.Equal("id", target.ID)
+		},
+	}
+
+	err = repo.WithTx(context.Background(), nil, func(txRepo qry.TypedRepository[model]) error {
+		_, err := txRepo.UpdateFn(context.Background(), func(query *qry.TypedUpdateQuery[model]) {
+			query.Target = &model{ID: 1, Name: "Tom"}
+		})
+		return err
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -3,6 +3,7 @@ package qry
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -14,6 +15,7 @@ type TypedRepository[T any] struct {
 	StandardUpdateCondition       func(target *T) Condition
 	StandardInsertValues          func(target *T) map[Field]any
 	StandardDeleteCondition       func(target *T) Condition
+	StandardPrimaryKeyValues      func(target *T) map[Field]any
 
 	PreScanFn   func(ctx context.Context, query Query, target *T) error
 	PostScanFn  func(ctx context.Context, query Query, target *T) error
@@ -42,23 +44,49 @@ func (repo TypedRepository[T]) DeleteQuery() TypedDeleteQuery[T] {
 func (repo TypedRepository[T]) ScanRow(ctx context.Context, query Query, scanner Scanner, destFn func(*T) []interface{}) (*T, error) {
 	result := new(T)
 
+	if err := repo.scanInto(ctx, query, scanner, result, destFn); err != nil {
+		var scanErr *scanError
+		if errors.As(err, &scanErr) {
+			return nil, err
+		}
+		return result, err
+	}
+
+	return result, nil
+}
+
+// scanError is returned by scanInto when the scanner fails, as opposed to one of the hooks.
+type scanError struct {
+	err error
+}
+
+func (e *scanError) Error() string {
+	return fmt.Sprintf("could not scan row: %v", e.err)
+}
+
+func (e *scanError) Unwrap() error {
+	return e.err
+}
+
+// scanInto scans the current row into the given target, running the scan hooks either side.
+func (repo TypedRepository[T]) scanInto(ctx context.Context, query Query, scanner Scanner, target *T, destFn func(*T) []interface{}) error {
 	if repo.PreScanFn != nil {
-		if err := repo.PreScanFn(ctx, query, result); err != nil {
-			return result, fmt.Errorf("pre scan hook failed: %w", err)
+		if err := repo.PreScanFn(ctx, query, target); err != nil {
+			return fmt.Errorf("pre scan hook failed: %w", err)
 		}
 	}
 
-	if err := scanner.Scan(destFn(result)...); err != nil {
-		return nil, fmt.Errorf("could not scan row: %w", err)
+	if err := scanner.Scan(destFn(target)...); err != nil {
+		return &scanError{err: err}
 	}
 
 	if repo.PostScanFn != nil {
-		if err := repo.PostScanFn(ctx, query, result); err != nil {
-			return result, fmt.Errorf("post scan hook failed: %w", err)
+		if err := repo.PostScanFn(ctx, query, target); err != nil {
+			return fmt.Errorf("post scan hook failed: %w", err)
 		}
 	}
 
-	return result, nil
+	return nil
 }
 
 func (repo TypedRepository[T]) QueryRowFn(ctx context.Context, queryFn func(*TypedSelectQuery[T])) (*T, error) {
@@ -88,6 +116,10 @@ func (repo TypedRepository[T]) QueryRow(ctx context.Context, query TypedSelectQu
 		}
 	}
 
+	// The row is scanned before returning, so the timeout can be released once it has been read.
+	ctx, cancel := repo.withQueryTimeout(ctx)
+	defer cancel()
+
 	row, err := repo.Repository.QueryRow(ctx, query.Prepare())
 	if err != nil {
 		return nil, err
@@ -182,7 +214,55 @@ func (repo TypedRepository[T]) Insert(ctx context.Context, query TypedInsertQuer
 		}
 	}
 
-	return repo.Repository.Insert(ctx, query.Prepare())
+	queries := query.splitByColumns(repo.dialect())
+	if len(queries) == 1 {
+		return repo.Repository.Insert(ctx, query.Prepare())
+	}
+
+	// Insert the targets of each statement within a transaction so that either every target is inserted
+	// or none are.
+	results := make(insertResults, 0, len(queries))
+	err := repo.Repository.WithTx(ctx, nil, func(txRepo Repository) error {
+		for _, query := range queries {
+			result, err := txRepo.Insert(ctx, query.Prepare())
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Upsert inserts the given targets. When a target conflicts with an existing row on the given conflict fields,
+// every other inserted field is updated to the value that would have been inserted.
+func (repo TypedRepository[T]) Upsert(ctx context.Context, conflictFields []Field, targets ...*T) (sql.Result, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "Upsert")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	query := repo.prepareInsertQuery(repo.InsertQuery())
+	query.Targets = targets
+
+	update := make(map[Field]any)
+	for _, field := range query.Prepare().Fields {
+		if !containsField(conflictFields, field) {
+			update[field] = Excluded(field)
+		}
+	}
+
+	query.OnConflict = &OnConflict{
+		Fields: conflictFields,
+		Update: update,
+	}
+
+	return repo.Insert(ctx, query)
 }
 
 func (repo TypedRepository[T]) QueryFn(ctx context.Context, queryFn func(*TypedSelectQuery[T])) ([]*T, error) {
@@ -204,33 +284,32 @@ func (repo TypedRepository[T]) Query(ctx context.Context, query TypedSelectQuery
 		defer span.End()
 	}
 
-	query = repo.prepareSelectQuery(query)
-
-	if repo.PreSelectFn != nil {
-		if err := repo.PreSelectFn(ctx, query); err != nil {
-			return nil, fmt.Errorf("pre select hook failed: %w", err)
-		}
-	}
-
-	rows, err := repo.Repository.Query(ctx, query.Prepare())
+	cursor, err := repo.Cursor(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
-	results := make([]*T, 0)
+	return cursor.all()
+}
 
-	for rows.Next() {
-		result, err := repo.ScanRow(ctx, query, rows, query.FieldReferences)
+// QueryCompound executes the given CompoundQuery and scans each row using StandardSelectFieldReferences.
+// Queries within it that do not set a Table or Fields use those of the repository, so each row must be
+// made up of the StandardSelectFields.
+func (repo TypedRepository[T]) QueryCompound(ctx context.Context, query CompoundQuery) ([]*T, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "QueryCompound")
+		ctx = spanCtx
+		defer span.End()
+	}
 
-		if err != nil {
-			return results, err
-		}
+	query.Queries = genericMap(query.Queries, repo.Repository.prepareSelectQuery)
 
-		results = append(results, result)
+	cursor, err := repo.openCursor(ctx, query, query, repo.StandardSelectFieldReferences)
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return cursor.all()
 }
 
 func (repo TypedRepository[T]) prepareSelectQuery(query TypedSelectQuery[T]) TypedSelectQuery[T] {
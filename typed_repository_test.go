@@ -33,8 +33,7 @@ func TestTypedRepository_QueryRow(t *testing.T) {
 				Name: "Tom",
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
 							AddRow(int64(1), "Tom"),
@@ -55,8 +54,7 @@ func TestTypedRepository_QueryRow(t *testing.T) {
 				Name: "Tom",
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT user_id, username FROM user").
-					ExpectQuery().
+				db.ExpectQuery("SELECT user_id, username FROM user").
 					WillReturnRows(
 						sqlmock.NewRows([]string{"user_id", "username"}).
 							AddRow(1, "Tom"),
@@ -77,8 +75,7 @@ func TestTypedRepository_QueryRow(t *testing.T) {
 			},
 
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT id, name FROM users WHERE id = ?").
-					ExpectQuery().
+				db.ExpectQuery("SELECT id, name FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"id", "name"}).
@@ -102,8 +99,7 @@ func TestTypedRepository_QueryRow(t *testing.T) {
 				Name: "Tom",
 			},
 			mockFn: func(db sqlmock.Sqlmock) {
-				db.ExpectPrepare("SELECT name FROM users WHERE id = ?").
-					ExpectQuery().
+				db.ExpectQuery("SELECT name FROM users WHERE id = ?").
 					WithArgs(1).
 					WillReturnRows(
 						sqlmock.NewRows([]string{"name"}).
@@ -155,3 +151,42 @@ func TestTypedRepository_QueryRow(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedRepository_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectPrepare("INSERT INTO users(id, name) VALUES ($1, $2), ($3, $4) ON CONFLICT (id) DO UPDATE SET name = excluded.name").
+		ExpectExec().
+		WithArgs(int64(1), "Tom", int64(2), "Jim").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := qry.TypedRepository[model]{
+		Repository: qry.Repository{
+			DB:      db,
+			Table:   "users",
+			Dialect: qry.Postgres,
+		},
+		StandardInsertValues: func(target *model) map[qry.Field]any {
+			return map[qry.Field]any{
+				"id":   target.ID,
+				"name": target.Name,
+			}
+		},
+	}
+
+	_, err = repo.Upsert(context.Background(), []qry.Field{"id"}, &model{ID: 1, Name: "Tom"}, &model{ID: 2, Name: "Jim"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
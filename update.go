@@ -2,51 +2,116 @@ package qry
 
 import (
 	"fmt"
-	"strings"
 )
 
 func Update() UpdateQuery {
 	return UpdateQuery{}
 }
 
+// UpdateQuery is a Query.
+// Values are rendered in order of field name so that the same query always produces the same SQL.
 type UpdateQuery struct {
-	Values    map[Field]any
-	Table     string
+	// With is the common table expressions available to the query.
+	With []CTE
+	// Values is the value set for each field. Use an Expr to set a field relative to its current value.
+	Values map[Field]any
+	Table  string
+	// Join is the tables joined to Table, whose fields can be used in Values and Condition.
+	// Dialects without FeatureMutationJoin render inner joins with FeatureUpdateFrom, in which case
+	// the fields set by Values cannot be qualified with the table name.
+	Join      []Join
 	Limit     int64
 	Offset    int64
 	Condition Condition
+	// Returning is the fields returned for each updated row.
+	Returning []Field
 }
 
 func (query UpdateQuery) Build() (string, []any) {
-	stmt := fmt.Sprintf(
-		"UPDATE %s SET ",
-		query.Table,
-	)
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
 
-	args := make([]any, 0)
+func (query UpdateQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
 
-	for field, value := range query.Values {
-		stmt += fmt.Sprintf("%s = ?, ", field)
-		args = append(args, value)
+func (query UpdateQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	if query.Limit > 0 || query.Offset > 0 {
+		if err := requireFeature(dialect, FeatureMutationLimit); err != nil {
+			return "", nil, err
+		}
 	}
-	stmt = strings.TrimRight(stmt, ", ")
 
-	if query.Condition != nil {
-		if conditionsStmt, conditionArgs := query.Condition.Build(); len(conditionsStmt) > 0 {
-			stmt += fmt.Sprintf(" WHERE %s", conditionsStmt)
-			args = append(args, conditionArgs...)
+	withStmt, args, err := buildWith(query.With, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	joinStmt, fromStmt, condition := "", "", query.Condition
+	fromArgs := make([]any, 0)
+	switch {
+	case len(query.Join) == 0:
+	case dialect.Supports(FeatureMutationJoin):
+		if query.Limit > 0 || query.Offset > 0 {
+			return "", nil, fmt.Errorf("%w: an update with joins cannot use a limit or offset", ErrInvalidQuery)
+		}
+		var joinArgs []any
+		joinStmt, joinArgs, err = buildJoins(query.Join, dialect)
+		if err != nil {
+			return "", nil, err
 		}
+		args = append(args, joinArgs...)
+	case dialect.Supports(FeatureUpdateFrom):
+		sources, sourceArgs, joinConditions, err := buildJoinSources(query.Join, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		fromStmt = " FROM " + sources
+		fromArgs = sourceArgs
+		condition = joinCondition(joinConditions, condition)
+	default:
+		return "", nil, requireFeature(dialect, FeatureMutationJoin)
 	}
 
-	if query.Limit > 0 {
-		stmt += fmt.Sprintf(" LIMIT %d", query.Limit)
+	assignments, assignmentArgs, err := buildAssignments(query.Values, dialect)
+	if err != nil {
+		return "", nil, err
 	}
+	args = append(args, assignmentArgs...)
+	args = append(args, fromArgs...)
 
-	if query.Offset > 0 {
-		stmt += fmt.Sprintf(" OFFSET %d", query.Offset)
+	outputStmt, returningStmt, err := buildReturning(query.Returning, "INSERTED", dialect)
+	if err != nil {
+		return "", nil, err
 	}
 
-	return stmt, args
+	stmt := fmt.Sprintf(
+		"%sUPDATE %s%s SET %s%s%s",
+		withStmt,
+		identifier(dialect, query.Table),
+		joinStmt,
+		assignments,
+		outputStmt,
+		fromStmt,
+	)
+
+	if condition != nil {
+		conditionsStmt, conditionArgs, err := buildPart(condition, dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(conditionsStmt) > 0 {
+			stmt += fmt.Sprintf(" WHERE %s", conditionsStmt)
+			args = append(args, conditionArgs...)
+		}
+	}
+
+	stmt += limitOffset(query.Limit, query.Offset)
+
+	stmt += returningStmt
+
+	return stmt, args, nil
 }
 
 type TypedUpdateQuery[T any] struct {
@@ -55,6 +120,8 @@ type TypedUpdateQuery[T any] struct {
 	Values    func(target *T) map[Field]any
 	Condition func(target *T) Condition
 	Target    *T
+	// ReturningReferences returns references to the fields of the target that Returning is scanned into.
+	ReturningReferences func(target *T) []any
 }
 
 func (query TypedUpdateQuery[T]) Prepare() UpdateQuery {
@@ -66,3 +133,11 @@ func (query TypedUpdateQuery[T]) Prepare() UpdateQuery {
 func (query TypedUpdateQuery[T]) Build() (string, []any) {
 	return query.Prepare().Build()
 }
+
+func (query TypedUpdateQuery[T]) BuildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().BuildDialect(dialect)
+}
+
+func (query TypedUpdateQuery[T]) buildDialect(dialect Dialect) (string, []any, error) {
+	return query.Prepare().buildDialect(dialect)
+}
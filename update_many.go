@@ -0,0 +1,272 @@
+package qry
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// FeatureUpdateFromValues is support for UPDATE ... FROM (VALUES ...), which UpdateManyQuery uses in place
+// of a CASE expression per field.
+const FeatureUpdateFromValues Feature = "update_from_values"
+
+// UpdateManyQuery is a Query that updates many rows with different values in a single statement.
+//
+// Dialects with FeatureUpdateFromValues join the table to a VALUES list. Since the VALUES list is not
+// within the scope of the table, values cannot be an Expr referencing the current row in these dialects.
+// Other dialects set each field with a CASE expression keyed on the Key fields.
+type UpdateManyQuery struct {
+	Table string
+	// Key is the fields that identify each row, such as the primary key.
+	Key []Field
+	// Fields is the fields set on each row.
+	Fields []Field
+	// Values is the values of each row: the value of each Key field followed by the value of each of the Fields.
+	Values [][]any
+}
+
+func (query UpdateManyQuery) Build() (string, []any) {
+	stmt, args, _ := query.BuildDialect(MySQL)
+	return stmt, args
+}
+
+func (query UpdateManyQuery) BuildDialect(dialect Dialect) (string, []any, error) {
+	return buildStatement(query, dialect)
+}
+
+func (query UpdateManyQuery) buildDialect(dialect Dialect) (string, []any, error) {
+	if len(query.Key) == 0 {
+		return "", nil, fmt.Errorf("%w: update many requires a key", ErrInvalidQuery)
+	}
+	if len(query.Fields) == 0 {
+		return "", nil, fmt.Errorf("%w: update many requires at least one field", ErrInvalidQuery)
+	}
+	if len(query.Values) == 0 {
+		return "", nil, fmt.Errorf("%w: update many requires at least one row", ErrInvalidQuery)
+	}
+	for _, row := range query.Values {
+		if len(row) != len(query.Key)+len(query.Fields) {
+			return "", nil, fmt.Errorf("%w: update many expected %d values per row, got %d", ErrInvalidQuery, len(query.Key)+len(query.Fields), len(row))
+		}
+	}
+
+	if dialect.Supports(FeatureUpdateFromValues) {
+		return query.buildFromValues(dialect)
+	}
+	return query.buildCase(dialect)
+}
+
+// buildCase renders the query as UPDATE t SET a = CASE WHEN key = ? THEN ? ... END WHERE key IN (?, ...).
+func (query UpdateManyQuery) buildCase(dialect Dialect) (string, []any, error) {
+	keyConditions := make([]Condition, len(query.Values))
+	for i, row := range query.Values {
+		keyConditions[i] = query.keyCondition(row)
+	}
+
+	args := make([]any, 0)
+	assignments := make([]string, len(query.Fields))
+	for i, field := range query.Fields {
+		cases := make([]string, len(query.Values))
+		for j, row := range query.Values {
+			keyStmt, keyArgs, err := buildPart(keyConditions[j], dialect)
+			if err != nil {
+				return "", nil, err
+			}
+			valueStmt, valueArgs, err := buildValue(row[len(query.Key)+i], dialect)
+			if err != nil {
+				return "", nil, err
+			}
+			cases[j] = fmt.Sprintf("WHEN %s THEN %s", keyStmt, valueStmt)
+			args = append(args, keyArgs...)
+			args = append(args, valueArgs...)
+		}
+		assignments[i] = fmt.Sprintf("%s = CASE %s END", identifier(dialect, field), strings.Join(cases, " "))
+	}
+
+	var condition Condition
+	if len(query.Key) == 1 {
+		condition = In(query.Key[0], genericMap(query.Values, func(row []any) any {
+			return row[0]
+		}))
+	} else {
+		condition = Or(keyConditions...)
+	}
+	conditionStmt, conditionArgs, err := buildPart(condition, dialect)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, conditionArgs...)
+
+	stmt := fmt.Sprintf(
+		"UPDATE %s SET %s WHERE %s",
+		identifier(dialect, query.Table),
+		strings.Join(assignments, ", "),
+		conditionStmt,
+	)
+
+	return stmt, args, nil
+}
+
+// keyCondition returns a Condition matching the Key values of the given row.
+func (query UpdateManyQuery) keyCondition(row []any) Condition {
+	conditions := make([]Condition, len(query.Key))
+	for i, field := range query.Key {
+		conditions[i] = Equal(field, row[i])
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return And(conditions...)
+}
+
+// buildFromValues renders the query as UPDATE t SET a = v.a FROM (VALUES (?, ?), ...) AS v(key, a) WHERE t.key = v.key.
+func (query UpdateManyQuery) buildFromValues(dialect Dialect) (string, []any, error) {
+	const alias = "qry_values"
+	table := identifier(dialect, query.Table)
+
+	columns := make([]Field, 0, len(query.Key)+len(query.Fields))
+	columns = append(columns, query.Key...)
+	columns = append(columns, query.Fields...)
+
+	// The types of bound values within a VALUES list are not known, so the list starts with a row of NULLs
+	// cast to the types of the table columns. The row never matches as NULL is not equal to any key.
+	rows := make([]string, 0, len(query.Values)+1)
+	rows = append(rows, fmt.Sprintf("(%s)", strings.Join(genericMap(columns, func(column Field) string {
+		return fmt.Sprintf("(NULL::%s).%s", table, identifier(dialect, column))
+	}), ", ")))
+
+	args := make([]any, 0)
+	for _, row := range query.Values {
+		valueStmts := make([]string, len(row))
+		for i, value := range row {
+			valueStmt, valueArgs, err := buildValue(value, dialect)
+			if err != nil {
+				return "", nil, err
+			}
+			valueStmts[i] = valueStmt
+			args = append(args, valueArgs...)
+		}
+		rows = append(rows, fmt.Sprintf("(%s)", strings.Join(valueStmts, ", ")))
+	}
+
+	assignments := genericMap(query.Fields, func(field Field) string {
+		return fmt.Sprintf("%s = %s.%s", identifier(dialect, field), alias, identifier(dialect, field))
+	})
+	conditions := genericMap(query.Key, func(field Field) string {
+		return fmt.Sprintf("%s.%s = %s.%s", table, identifier(dialect, field), alias, identifier(dialect, field))
+	})
+
+	stmt := fmt.Sprintf(
+		"UPDATE %s SET %s FROM (VALUES %s) AS %s(%s) WHERE %s",
+		table,
+		strings.Join(assignments, ", "),
+		strings.Join(rows, ", "),
+		alias,
+		identifiers(dialect, columns),
+		strings.Join(conditions, " AND "),
+	)
+
+	return stmt, args, nil
+}
+
+// UpdateMany executes the given query, updating each of its rows with their own values.
+func (repo Repository) UpdateMany(ctx context.Context, query UpdateManyQuery) (sql.Result, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "UpdateMany")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	if query.Table == "" {
+		query.Table = repo.Table
+	}
+
+	if repo.PreUpdateFn != nil {
+		if err := repo.PreUpdateFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre update hook failed: %w", err)
+		}
+	}
+
+	return repo.Exec(ctx, query)
+}
+
+// UpdateMany updates each of the given targets in a single statement, matching rows on the values returned by
+// StandardPrimaryKeyValues and setting the values returned by StandardUpdateValues.
+// Every target must return the same update fields. Without targets nothing is executed and no rows are affected.
+func (repo TypedRepository[T]) UpdateMany(ctx context.Context, targets []*T) (sql.Result, error) {
+	if repo.Tracer != nil {
+		spanCtx, span := repo.Tracer.Start(ctx, "UpdateMany")
+		ctx = spanCtx
+		defer span.End()
+	}
+
+	if len(targets) == 0 {
+		return driver.RowsAffected(0), nil
+	}
+
+	query, err := repo.updateManyQuery(targets)
+	if err != nil {
+		return nil, err
+	}
+
+	if repo.PreUpdateFn != nil {
+		if err := repo.PreUpdateFn(ctx, query); err != nil {
+			return nil, fmt.Errorf("pre update hook failed: %w", err)
+		}
+	}
+
+	return repo.Repository.UpdateMany(ctx, query)
+}
+
+// updateManyQuery returns an UpdateManyQuery for the given targets.
+// Fields are sorted so that the same targets always produce the same SQL.
+func (repo TypedRepository[T]) updateManyQuery(targets []*T) (UpdateManyQuery, error) {
+	if repo.StandardPrimaryKeyValues == nil {
+		return UpdateManyQuery{}, fmt.Errorf("%w: update many requires StandardPrimaryKeyValues", ErrInvalidQuery)
+	}
+	if repo.StandardUpdateValues == nil {
+		return UpdateManyQuery{}, fmt.Errorf("%w: update many requires StandardUpdateValues", ErrInvalidQuery)
+	}
+
+	query := UpdateManyQuery{
+		Table:  repo.Table,
+		Values: make([][]any, 0, len(targets)),
+	}
+
+	for i, target := range targets {
+		keyValues := repo.StandardPrimaryKeyValues(target)
+		updateValues := repo.StandardUpdateValues(target)
+		for field := range keyValues {
+			delete(updateValues, field)
+		}
+
+		if i == 0 {
+			query.Key = sortedFields(keyValues)
+			query.Fields = sortedFields(updateValues)
+		}
+		if len(keyValues) != len(query.Key) || len(updateValues) != len(query.Fields) {
+			return UpdateManyQuery{}, fmt.Errorf("%w: update many requires every target to update the same fields", ErrInvalidQuery)
+		}
+
+		row := make([]any, 0, len(query.Key)+len(query.Fields))
+		for _, field := range query.Key {
+			value, ok := keyValues[field]
+			if !ok {
+				return UpdateManyQuery{}, fmt.Errorf("%w: update many requires every target to have the same key", ErrInvalidQuery)
+			}
+			row = append(row, value)
+		}
+		for _, field := range query.Fields {
+			value, ok := updateValues[field]
+			if !ok {
+				return UpdateManyQuery{}, fmt.Errorf("%w: update many requires every target to update the same fields", ErrInvalidQuery)
+			}
+			row = append(row, value)
+		}
+		query.Values = append(query.Values, row)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,194 @@
+package qry_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestUpdateManyQuery_BuildDialect(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.UpdateManyQuery
+		dialect qry.Dialect
+		expStmt string
+		expArgs []any
+		expErr  error
+	}
+
+	users := qry.UpdateManyQuery{
+		Table:  "users",
+		Key:    []qry.Field{"id"},
+		Fields: []qry.Field{"email", "name"},
+		Values: [][]any{
+			{1, "tom@example.com", "Tom"},
+			{2, "jim@example.com", "Jim"},
+		},
+	}
+
+	tests := []def{
+		{
+			name:    "MySQL",
+			query:   users,
+			dialect: qry.MySQL,
+			expStmt: "UPDATE users SET email = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? END, name = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? END WHERE id IN (?, ?)",
+			expArgs: []any{1, "tom@example.com", 2, "jim@example.com", 1, "Tom", 2, "Jim", 1, 2},
+		},
+		{
+			name:    "SQL Server",
+			query:   users,
+			dialect: qry.SQLServer,
+			expStmt: "UPDATE users SET email = CASE WHEN id = @p1 THEN @p2 WHEN id = @p3 THEN @p4 END, name = CASE WHEN id = @p5 THEN @p6 WHEN id = @p7 THEN @p8 END WHERE id IN (@p9, @p10)",
+			expArgs: []any{1, "tom@example.com", 2, "jim@example.com", 1, "Tom", 2, "Jim", 1, 2},
+		},
+		{
+			name:    "Postgres",
+			query:   users,
+			dialect: qry.Postgres,
+			expStmt: "UPDATE users SET email = qry_values.email, name = qry_values.name FROM (VALUES ((NULL::users).id, (NULL::users).email, (NULL::users).name), ($1, $2, $3), ($4, $5, $6)) AS qry_values(id, email, name) WHERE users.id = qry_values.id",
+			expArgs: []any{1, "tom@example.com", "Tom", 2, "jim@example.com", "Jim"},
+		},
+		{
+			name: "SQLite composite key",
+			query: qry.UpdateManyQuery{
+				Table:  "memberships",
+				Key:    []qry.Field{"user_id", "group_id"},
+				Fields: []qry.Field{"role"},
+				Values: [][]any{
+					{1, 2, "admin"},
+					{1, 3, "member"},
+				},
+			},
+			dialect: qry.SQLite,
+			expStmt: "UPDATE memberships SET role = CASE WHEN (user_id = ? AND group_id = ?) THEN ? WHEN (user_id = ? AND group_id = ?) THEN ? END WHERE ((user_id = ? AND group_id = ?) OR (user_id = ? AND group_id = ?))",
+			expArgs: []any{1, 2, "admin", 1, 3, "member", 1, 2, 1, 3},
+		},
+		{
+			name: "Postgres composite key",
+			query: qry.UpdateManyQuery{
+				Table:  "memberships",
+				Key:    []qry.Field{"user_id", "group_id"},
+				Fields: []qry.Field{"role"},
+				Values: [][]any{
+					{1, 2, "admin"},
+				},
+			},
+			dialect: qry.Postgres,
+			expStmt: "UPDATE memberships SET role = qry_values.role FROM (VALUES ((NULL::memberships).user_id, (NULL::memberships).group_id, (NULL::memberships).role), ($1, $2, $3)) AS qry_values(user_id, group_id, role) WHERE memberships.user_id = qry_values.user_id AND memberships.group_id = qry_values.group_id",
+			expArgs: []any{1, 2, "admin"},
+		},
+		{
+			name: "Expression values",
+			query: qry.UpdateManyQuery{
+				Table:  "users",
+				Key:    []qry.Field{"id"},
+				Fields: []qry.Field{"updated_at"},
+				Values: [][]any{
+					{1, qry.Now()},
+				},
+			},
+			dialect: qry.MySQL,
+			expStmt: "UPDATE users SET updated_at = CASE WHEN id = ? THEN CURRENT_TIMESTAMP END WHERE id IN (?)",
+			expArgs: []any{1, 1},
+		},
+		{
+			name: "No rows",
+			query: qry.UpdateManyQuery{
+				Table:  "users",
+				Key:    []qry.Field{"id"},
+				Fields: []qry.Field{"name"},
+			},
+			dialect: qry.MySQL,
+			expErr:  qry.ErrInvalidQuery,
+		},
+		{
+			name: "Wrong number of values",
+			query: qry.UpdateManyQuery{
+				Table:  "users",
+				Key:    []qry.Field{"id"},
+				Fields: []qry.Field{"name"},
+				Values: [][]any{{1}},
+			},
+			dialect: qry.Postgres,
+			expErr:  qry.ErrInvalidQuery,
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs, err := tc.query.BuildDialect(tc.dialect)
+			if tc.expErr != nil {
+				if !errors.Is(err, tc.expErr) {
+					t.Errorf("expected error %v, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args")
+		})
+	}
+}
+
+func TestTypedRepository_UpdateMany(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Errorf("could not init db mock")
+		return
+	}
+
+	defer db.Close()
+
+	mock.ExpectPrepare("UPDATE users SET name = CASE WHEN id = ? THEN ? WHEN id = ? THEN ? END WHERE id IN (?, ?)").
+		ExpectExec().
+		WithArgs(int64(1), "Tom", int64(2), "Jim", int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo, err := qry.NewTypedRepositoryFromTags[taggedUser](qry.Repository{
+		DB:    db,
+		Table: "users",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	_, err = repo.UpdateMany(context.Background(), []*taggedUser{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim"}})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	_, err = repo.UpdateMany(context.Background(), []*taggedUser{{ID: 1, Name: "Tom"}, {ID: 2, Name: "Jim", Nickname: "Jimbo"}})
+	if !errors.Is(err, qry.ErrInvalidQuery) {
+		t.Errorf("expected error %v, got %v", qry.ErrInvalidQuery, err)
+	}
+
+	result, err := repo.UpdateMany(context.Background(), nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	checkDiffMsg(t, int64(0), affected, "invalid rows affected")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
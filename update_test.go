@@ -0,0 +1,60 @@
+package qry_test
+
+import (
+	"github.com/TomWright/qry"
+	"testing"
+)
+
+func TestUpdateQuery_Build(t *testing.T) {
+	type def struct {
+		name    string
+		query   qry.UpdateQuery
+		expStmt string
+		expArgs []any
+	}
+	tests := []def{
+		{
+			name: "Values are sorted by field",
+			query: qry.UpdateQuery{
+				Table: "users",
+				Values: map[qry.Field]any{
+					"name":       "Tom",
+					"age":        30,
+					"updated_at": "now",
+					"email":      "tom@example.com",
+				},
+				Condition: qry.Equal("id", 1),
+			},
+			expStmt: "UPDATE users SET age = ?, email = ?, name = ?, updated_at = ? WHERE id = ?",
+			expArgs: []any{30, "tom@example.com", "Tom", "now", 1},
+		},
+	}
+
+	for _, test := range tests {
+		tc := test
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			gotStmt, gotArgs := tc.query.Build()
+
+			if !checkDiffMsg(t, tc.expStmt, gotStmt, "invalid statement") {
+				return
+			}
+
+			if !checkDiffMsg(t, tc.expArgs, gotArgs, "invalid args") {
+				return
+			}
+
+			// Repeated builds must produce byte-identical SQL.
+			for i := 0; i < 50; i++ {
+				repeatStmt, repeatArgs := tc.query.Build()
+				if !checkDiffMsg(t, gotStmt, repeatStmt, "statement changed between builds") {
+					return
+				}
+				if !checkDiffMsg(t, gotArgs, repeatArgs, "args changed between builds") {
+					return
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package qry
+
+import (
+	"fmt"
+)
+
+const (
+	// FeatureOnDuplicateKeyUpdate is support for ON DUPLICATE KEY UPDATE and INSERT IGNORE.
+	FeatureOnDuplicateKeyUpdate Feature = "on_duplicate_key_update"
+	// FeatureOnConflict is support for ON CONFLICT DO UPDATE and ON CONFLICT DO NOTHING.
+	FeatureOnConflict Feature = "on_conflict"
+)
+
+// OnConflict controls what an InsertQuery does when a row conflicts with an existing row.
+type OnConflict struct {
+	// Fields is the conflict target, such as the columns of a unique index.
+	// It is required by dialects using ON CONFLICT when Update is set.
+	Fields []Field
+	// Update is the values set on the existing row.
+	// Use Excluded to reference the value that would have been inserted.
+	// When empty the existing row is left untouched.
+	Update map[Field]any
+}
+
+// ExcludedValue is a value that references the value a conflicting insert would have set for the Field.
+type ExcludedValue struct {
+	Field Field
+}
+
+// Excluded returns a value that references the value a conflicting insert would have set for the given field.
+// It can only be used within OnConflict.Update.
+func Excluded(field Field) ExcludedValue {
+	return ExcludedValue{
+		Field: field,
+	}
+}
+
+// ExcludedValues returns update values that set each of the given fields to the value that would have been inserted.
+func ExcludedValues(fields ...Field) map[Field]any {
+	values := make(map[Field]any, len(fields))
+	for _, field := range fields {
+		values[field] = Excluded(field)
+	}
+	return values
+}
+
+func (value ExcludedValue) buildValue(dialect Dialect) (string, []any, error) {
+	switch {
+	case dialect.Supports(FeatureOnConflict):
+		return fmt.Sprintf("excluded.%s", identifier(dialect, value.Field)), []any{}, nil
+	case dialect.Supports(FeatureOnDuplicateKeyUpdate):
+		return fmt.Sprintf("VALUES(%s)", identifier(dialect, value.Field)), []any{}, nil
+	default:
+		return "", nil, requireFeature(dialect, FeatureOnConflict)
+	}
+}
+
+// buildInsertConflict returns the clause appended to an insert to handle conflicts, and whether the
+// insert should be rendered as INSERT IGNORE.
+func buildInsertConflict(query InsertQuery, dialect Dialect) (string, []any, bool, error) {
+	if query.Ignore && query.OnConflict != nil {
+		return "", nil, false, fmt.Errorf("%w: insert cannot use both Ignore and OnConflict", ErrInvalidQuery)
+	}
+
+	switch {
+	case query.Ignore && dialect.Supports(FeatureOnConflict):
+		return " ON CONFLICT DO NOTHING", []any{}, false, nil
+
+	case query.Ignore && dialect.Supports(FeatureOnDuplicateKeyUpdate):
+		return "", []any{}, true, nil
+
+	case query.Ignore:
+		return "", nil, false, requireFeature(dialect, FeatureOnConflict)
+
+	case query.OnConflict == nil:
+		return "", []any{}, false, nil
+
+	case dialect.Supports(FeatureOnConflict):
+		if len(query.OnConflict.Update) == 0 {
+			if len(query.OnConflict.Fields) == 0 {
+				return " ON CONFLICT DO NOTHING", []any{}, false, nil
+			}
+			return fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", identifiers(dialect, query.OnConflict.Fields)), []any{}, false, nil
+		}
+		if len(query.OnConflict.Fields) == 0 {
+			return "", nil, false, fmt.Errorf("%w: %s requires conflict fields to update on conflict", ErrInvalidQuery, dialect.Name())
+		}
+		assignments, args, err := buildAssignments(query.OnConflict.Update, dialect)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", identifiers(dialect, query.OnConflict.Fields), assignments), args, false, nil
+
+	case dialect.Supports(FeatureOnDuplicateKeyUpdate):
+		if len(query.OnConflict.Update) == 0 {
+			// There is no DO NOTHING equivalent, so assign a conflict field to itself.
+			if len(query.OnConflict.Fields) == 0 {
+				return "", nil, false, fmt.Errorf("%w: %s requires a conflict field or update values", ErrInvalidQuery, dialect.Name())
+			}
+			field := query.OnConflict.Fields[0]
+			return fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s = %s", field, field), []any{}, false, nil
+		}
+		assignments, args, err := buildAssignments(query.OnConflict.Update, dialect)
+		if err != nil {
+			return "", nil, false, err
+		}
+		return fmt.Sprintf(" ON DUPLICATE KEY UPDATE %s", assignments), args, false, nil
+
+	default:
+		return "", nil, false, requireFeature(dialect, FeatureOnConflict)
+	}
+}
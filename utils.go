@@ -1,6 +1,7 @@
 package qry
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -24,3 +25,32 @@ func genericJoin[T stringer](elems []T, separator string) string {
 		separator,
 	)
 }
+
+// sortedFields returns the keys of the given map sorted by name so that generated SQL is deterministic.
+func sortedFields[T any](values map[Field]T) []Field {
+	fields := make([]Field, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i] < fields[j]
+	})
+	return fields
+}
+
+// containsField returns true if the given field exists in fields.
+func containsField(fields []Field, field Field) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZero returns true if the given value is the zero value of its type.
+// It allows generated code to check for zero values without reflection.
+func IsZero[V comparable](value V) bool {
+	var zero V
+	return value == zero
+}
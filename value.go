@@ -0,0 +1,47 @@
+package qry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// valueBuilder is implemented by values that are rendered inline within a statement rather than
+// being bound as a single arg.
+type valueBuilder interface {
+	buildValue(dialect Dialect) (string, []any, error)
+}
+
+// buildValue returns the SQL and args used to represent the given value.
+// Values are bound as a placeholder unless they implement valueBuilder or are a query that returns rows,
+// in which case they are rendered as a subquery.
+func buildValue(value any, dialect Dialect) (string, []any, error) {
+	switch v := value.(type) {
+	case valueBuilder:
+		return v.buildValue(dialect)
+	case rowQuery:
+		stmt, args, err := v.buildDialect(dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s)", stmt), args, nil
+	default:
+		return "?", []any{value}, nil
+	}
+}
+
+// buildAssignments returns a comma separated list of field = value assignments, in order of field name.
+func buildAssignments(values map[Field]any, dialect Dialect) (string, []any, error) {
+	parts := make([]string, 0, len(values))
+	args := make([]any, 0, len(values))
+
+	for _, field := range sortedFields(values) {
+		valueStmt, valueArgs, err := buildValue(values[field], dialect)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, fmt.Sprintf("%s = %s", identifier(dialect, field), valueStmt))
+		args = append(args, valueArgs...)
+	}
+
+	return strings.Join(parts, ", "), args, nil
+}